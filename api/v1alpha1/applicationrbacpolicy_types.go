@@ -0,0 +1,171 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// ConditionTypeReady reports whether the policy is valid and used by the webhook.
+	ConditionTypeReady = "Ready"
+
+	// ReasonValid is set on the Ready condition of a valid policy.
+	ReasonValid = "Valid"
+	// ReasonConflict is set on the Ready condition when the namespace holds more than one policy.
+	ReasonConflict = "Conflict"
+	// ReasonPermissionProfileNotFound is set on the Ready condition when the permission profile does not exist.
+	ReasonPermissionProfileNotFound = "PermissionProfileNotFound"
+	// ReasonInvalidClusterPattern is set on the Ready condition when an allowed cluster is a malformed pattern.
+	ReasonInvalidClusterPattern = "InvalidClusterPattern"
+	// ReasonInvalidManagementApplications is set on the Ready condition when the management Applications are
+	// defined by a malformed pattern or are not restricted to allowed destinations.
+	ReasonInvalidManagementApplications = "InvalidManagementApplications"
+)
+
+// PolicySubject is a subject that may administer the Argo instance.
+// +kubebuilder:validation:XValidation:rule="self.kind != 'ServiceAccount' || has(self.__namespace__)",message="namespace is required for ServiceAccount subjects"
+type PolicySubject struct {
+	// Kind of the subject.
+	// +kubebuilder:validation:Enum=User;Group;ServiceAccount
+	Kind string `json:"kind"`
+
+	// Name of the subject.
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// Namespace of the subject, only set for ServiceAccount subjects.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+}
+
+// ManagementApplications defines which Applications of the Argo instance are management Applications, which are
+// exempt from validation for the destinations they manage. An Application is a management Application when its name
+// matches any of the names, name templates or name patterns, and it carries all the required labels and annotations.
+// +kubebuilder:validation:XValidation:rule="!(has(self.names) || has(self.nameTemplates) || has(self.namePatterns)) || has(self.allowedDestinations)",message="allowedDestinations is required when names, nameTemplates or namePatterns are set"
+type ManagementApplications struct {
+	// Names of the management Applications. When names, name templates and name patterns are all empty, the
+	// Application named "<instanceName>-mgmt" is the management Application.
+	// +optional
+	Names []string `json:"names,omitempty"`
+
+	// NameTemplates are names of the management Applications in which the "{{instanceName}}" placeholder is replaced
+	// with the name of the Argo instance, e.g. "{{instanceName}}-bootstrap".
+	// +optional
+	NameTemplates []string `json:"nameTemplates,omitempty"`
+
+	// NamePatterns are regular expressions the whole name of a management Application must match, e.g. for the
+	// Applications generated by an ApplicationSet.
+	// +optional
+	NamePatterns []string `json:"namePatterns,omitempty"`
+
+	// MatchLabels are labels a management Application must carry.
+	// +optional
+	MatchLabels map[string]string `json:"matchLabels,omitempty"`
+
+	// MatchAnnotations are annotations a management Application must carry.
+	// +optional
+	MatchAnnotations map[string]string `json:"matchAnnotations,omitempty"`
+
+	// AllowedDestinations are the destinations the management Applications are exempt for. A management Application
+	// targeting any other destination is validated like any other Application. They are required when names, name
+	// templates or name patterns are set. When empty, the "<instanceName>-mgmt" Application is exempt for every
+	// destination.
+	// +optional
+	AllowedDestinations []ManagementDestination `json:"allowedDestinations,omitempty"`
+}
+
+// ManagementDestination is a destination managed by the management Applications.
+type ManagementDestination struct {
+	// Cluster is the name or server URL of the destination cluster. Glob patterns are supported. When empty, every
+	// cluster matches.
+	// +optional
+	Cluster string `json:"cluster,omitempty"`
+
+	// Namespace is the destination namespace. Glob patterns are supported. When empty, every namespace matches.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+}
+
+// ApplicationRBACPolicySpec defines the policy the Applications of an Argo instance namespace are validated against.
+type ApplicationRBACPolicySpec struct {
+	// InstanceName is the name of the Argo instance.
+	// +kubebuilder:validation:MinLength=1
+	InstanceName string `json:"instanceName"`
+
+	// Admins are the subjects administering the Argo instance. At least one of them must have access to the
+	// destination of every Application.
+	// +kubebuilder:validation:MinItems=1
+	Admins []PolicySubject `json:"admins"`
+
+	// AllowedClusters restricts the destination clusters of the Applications, by cluster name or server URL. Glob
+	// patterns are supported. When empty, every cluster is allowed.
+	// +optional
+	AllowedClusters []string `json:"allowedClusters,omitempty"`
+
+	// PermissionProfile is the name of the permission profile defining the access the admins must have on destination
+	// clusters without a profile configured by the administrator. When empty, the default profile is used.
+	// +optional
+	PermissionProfile string `json:"permissionProfile,omitempty"`
+
+	// ManagementApplications defines which Applications are exempt from validation.
+	// +optional
+	ManagementApplications *ManagementApplications `json:"managementApplications,omitempty"`
+}
+
+// ApplicationRBACPolicyStatus defines the observed state of ApplicationRBACPolicy.
+type ApplicationRBACPolicyStatus struct {
+	// ObservedGeneration is the generation of the policy the status was computed for.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Conditions report problems with the policy.
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:shortName=arp
+// +kubebuilder:printcolumn:name="Instance",type=string,JSONPath=`.spec.instanceName`
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// ApplicationRBACPolicy is the Schema for the applicationrbacpolicies API. It declares the tenant policy of the Argo
+// instance whose Applications live in its namespace, replacing the argo-config ConfigMap.
+type ApplicationRBACPolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ApplicationRBACPolicySpec   `json:"spec,omitempty"`
+	Status ApplicationRBACPolicyStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ApplicationRBACPolicyList contains a list of ApplicationRBACPolicy.
+type ApplicationRBACPolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ApplicationRBACPolicy `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ApplicationRBACPolicy{}, &ApplicationRBACPolicyList{})
+}
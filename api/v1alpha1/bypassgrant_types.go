@@ -0,0 +1,102 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// ConditionTypeActive reports whether the grant has not expired yet and bypasses validation.
+	ConditionTypeActive = "Active"
+
+	// ReasonGrantActive is set on the Active condition of a grant that has not expired.
+	ReasonGrantActive = "Active"
+	// ReasonGrantExpiringSoon is set on the Active condition of a grant that expires within the warning threshold.
+	ReasonGrantExpiringSoon = "ExpiringSoon"
+	// ReasonGrantExpired is set on the Active condition of an expired grant.
+	ReasonGrantExpired = "Expired"
+)
+
+// BypassGrantSpec defines the scope, justification and lifetime of a bypass of the validation.
+type BypassGrantSpec struct {
+	// Cluster restricts the grant to the destination cluster with the given name or server URL, compared
+	// case-insensitively. When empty, every destination cluster is bypassed.
+	// +optional
+	Cluster string `json:"cluster,omitempty"`
+
+	// DestinationNamespace restricts the grant to the given destination namespace. When empty, every destination
+	// namespace is bypassed.
+	// +optional
+	DestinationNamespace string `json:"destinationNamespace,omitempty"`
+
+	// Reason justifies the bypass.
+	// +kubebuilder:validation:MinLength=1
+	Reason string `json:"reason"`
+
+	// Approver is the user who created or last changed the scope of the grant. It is set by the webhook and any
+	// other value is overwritten.
+	// +optional
+	Approver string `json:"approver,omitempty"`
+
+	// ExpiresAt is when the grant stops bypassing validation.
+	ExpiresAt metav1.Time `json:"expiresAt"`
+}
+
+// BypassGrantStatus defines the observed state of BypassGrant.
+type BypassGrantStatus struct {
+	// ObservedGeneration is the generation of the grant the status was computed for.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Conditions report whether the grant is active.
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Cluster",type=string,JSONPath=`.spec.cluster`
+// +kubebuilder:printcolumn:name="Destination Namespace",type=string,JSONPath=`.spec.destinationNamespace`
+// +kubebuilder:printcolumn:name="Approver",type=string,JSONPath=`.spec.approver`
+// +kubebuilder:printcolumn:name="Expires At",type=date,JSONPath=`.spec.expiresAt`
+// +kubebuilder:printcolumn:name="Active",type=string,JSONPath=`.status.conditions[?(@.type=="Active")].status`
+
+// BypassGrant is the Schema for the bypassgrants API. It bypasses the validation of the Applications and AppProjects
+// in its namespace until it expires, optionally only for a single destination cluster and namespace.
+type BypassGrant struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   BypassGrantSpec   `json:"spec,omitempty"`
+	Status BypassGrantStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// BypassGrantList contains a list of BypassGrant.
+type BypassGrantList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []BypassGrant `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&BypassGrant{}, &BypassGrantList{})
+}
@@ -17,31 +17,43 @@ limitations under the License.
 package main
 
 import (
+	"context"
 	"crypto/tls"
 	"flag"
 	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
+	"time"
 
+	"github.com/dana-team/application-rbac-validator/internal/audit"
+	"github.com/dana-team/application-rbac-validator/internal/clusterclient"
 	"github.com/dana-team/application-rbac-validator/internal/common"
 	"github.com/dana-team/application-rbac-validator/internal/metrics"
+	"github.com/dana-team/application-rbac-validator/internal/revalidation"
+	"github.com/dana-team/application-rbac-validator/internal/tokens"
+	"github.com/dana-team/application-rbac-validator/internal/tracing"
+	"github.com/dana-team/application-rbac-validator/internal/utils"
 
 	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
 	// to ensure that exec-entrypoint and run can make use of them.
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
 
 	argoprojv1alpha1 "github.com/argoproj/argo-cd/v3/pkg/apis/application/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/certwatcher"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 	"sigs.k8s.io/controller-runtime/pkg/metrics/filters"
 	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 
+	rbacv1alpha1 "github.com/dana-team/application-rbac-validator/api/v1alpha1"
 	"github.com/dana-team/application-rbac-validator/internal/controller"
 	webhookargoprojv1alpha1 "github.com/dana-team/application-rbac-validator/internal/webhook/v1alpha1"
 	// +kubebuilder:scaffold:imports
@@ -56,6 +68,7 @@ func init() {
 	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
 
 	utilruntime.Must(argoprojv1alpha1.AddToScheme(scheme))
+	utilruntime.Must(rbacv1alpha1.AddToScheme(scheme))
 	// +kubebuilder:scaffold:scheme
 }
 
@@ -194,6 +207,11 @@ func main() {
 		HealthProbeBindAddress: probeAddr,
 		LeaderElection:         enableLeaderElection,
 		LeaderElectionID:       "2ebf7fb5.dana.io",
+		// Secrets are read from the API server rather than cached, so the Secrets of every namespace are not kept in
+		// memory. The cluster secret controller only watches their metadata.
+		Client: client.Options{
+			Cache: &client.CacheOptions{DisableFor: []client.Object{&corev1.Secret{}}},
+		},
 		// LeaderElectionReleaseOnCancel defines if the leader should step down voluntarily
 		// when the Manager ends. This requires the binary to immediately end when the
 		// Manager is stopped, otherwise, this setting is unsafe. Setting this significantly
@@ -217,22 +235,166 @@ func main() {
 		setupLog.Info(fmt.Sprintf("%s environment variable not set, using default value: %s", common.ClusterDomainEnvVarKey, common.DefaultServerUrlDomain))
 		serverUrlDomain = common.DefaultServerUrlDomain
 	}
+	enforcementMode := os.Getenv(common.EnforcementModeEnvVarKey)
+	if enforcementMode == "" {
+		enforcementMode = common.EnforcementModeEnforce
+	}
+	if !utils.IsValidEnforcementMode(enforcementMode) {
+		setupLog.Error(fmt.Errorf("invalid enforcement mode %q", enforcementMode), "unable to configure webhooks")
+		os.Exit(1)
+	}
+	clusterEnforcementModes, err := utils.ParseClusterEnforcementModes(os.Getenv(common.ClusterEnforcementModesEnvVarKey))
+	if err != nil {
+		setupLog.Error(err, "unable to configure webhooks")
+		os.Exit(1)
+	}
+	clusterClients := clusterclient.NewPool(clusterclient.DefaultIdleTimeout)
+	if err = mgr.Add(clusterClients); err != nil {
+		setupLog.Error(err, "unable to set up destination cluster client pool")
+		os.Exit(1)
+	}
+	tokenRequestExpiration, err := durationFromEnv(common.TokenRequestExpirationEnvVarKey, tokens.DefaultTokenExpiration)
+	if err == nil && tokenRequestExpiration < tokens.MinTokenExpiration {
+		err = fmt.Errorf("%s must be at least %s", common.TokenRequestExpirationEnvVarKey, tokens.MinTokenExpiration)
+	}
+	if err != nil {
+		setupLog.Error(err, "unable to set up destination token minting")
+		os.Exit(1)
+	}
+	destinationClients := tokens.NewMinter(clusterClients,
+		utils.SplitCommaSeparated(os.Getenv(common.TokenRequestAudiencesEnvVarKey)), tokenRequestExpiration)
+	tokenProbeInterval, err := durationFromEnv(common.TokenProbeIntervalEnvVarKey, tokens.DefaultProbeInterval)
+	if err != nil {
+		setupLog.Error(err, "unable to set up destination token monitor")
+		os.Exit(1)
+	}
+	tokenExpiryWarningThreshold, err := durationFromEnv(common.TokenExpiryWarningEnvVarKey,
+		tokens.DefaultExpiryWarningThreshold)
+	if err != nil {
+		setupLog.Error(err, "unable to set up destination token monitor")
+		os.Exit(1)
+	}
+	tokenMonitor := tokens.NewMonitor(mgr.GetClient(), destinationClients, mgr.GetEventRecorderFor(common.EventRecorderName),
+		serverUrlDomain, tokenProbeInterval, tokenExpiryWarningThreshold)
+	if err = mgr.Add(tokenMonitor); err != nil {
+		setupLog.Error(err, "unable to set up destination token monitor")
+		os.Exit(1)
+	}
+	if endpoint := os.Getenv(common.TracingOTLPEndpointEnvVarKey); endpoint != "" {
+		tracerProvider, err := tracing.NewProvider(context.Background(), endpoint,
+			os.Getenv(common.TracingOTLPInsecureEnvVarKey) == "true")
+		if err == nil {
+			err = mgr.Add(tracerProvider)
+		}
+		if err != nil {
+			setupLog.Error(err, "unable to set up tracing")
+			os.Exit(1)
+		}
+	}
+	auditor, err := newAuditor(mgr)
+	if err != nil {
+		setupLog.Error(err, "unable to set up auditing")
+		os.Exit(1)
+	}
+	validatorConfig := webhookargoprojv1alpha1.ValidatorConfig{
+		ServerUrlDomain:         serverUrlDomain,
+		EnforcementMode:         enforcementMode,
+		ClusterEnforcementModes: clusterEnforcementModes,
+		ClusterScopedValidation: os.Getenv(common.ClusterScopedValidationEnvVarKey) == "true",
+		ClusterSecretsNamespace: os.Getenv(common.ClusterSecretsNamespaceEnvVarKey),
+		ClusterClients:          destinationClients,
+		Recorder:                mgr.GetEventRecorderFor(common.EventRecorderName),
+		Auditor:                 auditor,
+	}
 	if os.Getenv("ENABLE_WEBHOOKS") != "false" {
-		if err = webhookargoprojv1alpha1.SetupApplicationWebhookWithManager(mgr, serverUrlDomain); err != nil {
+		if err = webhookargoprojv1alpha1.SetupApplicationWebhookWithManager(mgr, validatorConfig); err != nil {
 			setupLog.Error(err, "unable to create webhook", "webhook", "Application")
 			os.Exit(1)
 		}
+		if err = webhookargoprojv1alpha1.SetupApplicationSetWebhookWithManager(mgr, validatorConfig); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "ApplicationSet")
+			os.Exit(1)
+		}
+		if err = webhookargoprojv1alpha1.SetupAppProjectWebhookWithManager(mgr, validatorConfig); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "AppProject")
+			os.Exit(1)
+		}
+		bypassGrantMaxTTL, err := durationFromEnv(common.BypassGrantMaxTTLEnvVarKey, webhookargoprojv1alpha1.DefaultBypassGrantMaxTTL)
+		if err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "BypassGrant")
+			os.Exit(1)
+		}
+		bypassGrantConfig := webhookargoprojv1alpha1.BypassGrantConfig{
+			ApproverGroups: utils.SplitCommaSeparated(os.Getenv(common.BypassGrantApproversEnvVarKey)),
+			MaxTTL:         bypassGrantMaxTTL,
+		}
+		if err = webhookargoprojv1alpha1.SetupBypassGrantWebhookWithManager(mgr, bypassGrantConfig); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "BypassGrant")
+			os.Exit(1)
+		}
 	}
 	nsPrefix := os.Getenv("NAMESPACE_PREFIX")
 	if err = (&controller.ApplicationReconciler{
 		Client:          mgr.GetClient(),
 		Scheme:          mgr.GetScheme(),
+		Recorder:        mgr.GetEventRecorderFor(common.EventRecorderName),
 		NamespacePrefix: nsPrefix,
+		ServerUrlDomain: serverUrlDomain,
 	}).SetupWithManager(mgr); err != nil {
 
 		setupLog.Error(err, "unable to create controller", "controller", "Application")
 		os.Exit(1)
 	}
+	clusterSecretResyncPeriod, err := durationFromEnv(common.ClusterSecretResyncEnvVarKey,
+		controller.DefaultClusterSecretResyncPeriod)
+	if err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "ClusterSecret")
+		os.Exit(1)
+	}
+	if err = (&controller.ClusterSecretReconciler{
+		Client:          mgr.GetClient(),
+		Scheme:          mgr.GetScheme(),
+		Recorder:        mgr.GetEventRecorderFor(common.EventRecorderName),
+		NamespacePrefix: nsPrefix,
+		ResyncPeriod:    clusterSecretResyncPeriod,
+		ServerUrlDomain: serverUrlDomain,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "ClusterSecret")
+		os.Exit(1)
+	}
+	if err = (&controller.ApplicationRBACPolicyReconciler{
+		Client: mgr.GetClient(),
+		Scheme: mgr.GetScheme(),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "ApplicationRBACPolicy")
+		os.Exit(1)
+	}
+	bypassGrantExpiryWarningThreshold, err := durationFromEnv(common.BypassGrantExpiryWarningEnvVarKey,
+		controller.DefaultBypassGrantExpiryWarningThreshold)
+	if err != nil {
+		setupLog.Error(err, "unable to set up bypass grant monitoring")
+		os.Exit(1)
+	}
+	if err = (&controller.BypassGrantReconciler{
+		Client:                 mgr.GetClient(),
+		Scheme:                 mgr.GetScheme(),
+		Recorder:               mgr.GetEventRecorderFor(common.EventRecorderName),
+		ExpiryWarningThreshold: bypassGrantExpiryWarningThreshold,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "BypassGrant")
+		os.Exit(1)
+	}
+	revalidationInterval, err := durationFromEnv(common.RevalidationIntervalEnvVarKey, revalidation.DefaultInterval)
+	if err != nil {
+		setupLog.Error(err, "unable to set up Application re-validation")
+		os.Exit(1)
+	}
+	revalidator := revalidation.NewRevalidator(mgr.GetClient(), mgr.GetEventRecorderFor(common.EventRecorderName),
+		validatorConfig, nsPrefix, revalidationInterval, os.Getenv(common.RevalidationAutoSyncEnvVarKey) == "true")
+	if err = mgr.Add(revalidator); err != nil {
+		setupLog.Error(err, "unable to set up Application re-validation")
+		os.Exit(1)
+	}
 	// +kubebuilder:scaffold:builder
 
 	if metricsCertWatcher != nil {
@@ -266,3 +428,102 @@ func main() {
 		os.Exit(1)
 	}
 }
+
+// durationFromEnv parses the duration set in the given environment variable, returning the given default when it is
+// not set.
+func durationFromEnv(key string, defaultValue time.Duration) (time.Duration, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue, nil
+	}
+
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid duration %q in %s: %w", value, key, err)
+	}
+	if duration <= 0 {
+		return 0, fmt.Errorf("duration in %s must be positive", key)
+	}
+
+	return duration, nil
+}
+
+// intFromEnv parses the positive integer set in the given environment variable, returning the given default when it
+// is not set.
+func intFromEnv(key string, defaultValue int) (int, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue, nil
+	}
+
+	number, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid integer %q in %s: %w", value, key, err)
+	}
+	if number <= 0 {
+		return 0, fmt.Errorf("integer in %s must be positive", key)
+	}
+
+	return number, nil
+}
+
+// newAuditor returns an Auditor emitting to the sinks selected in the audit environment variables, or nil when no
+// sink is selected. The HTTP sink is added to the manager, so its batches are sent while the manager runs.
+func newAuditor(mgr ctrl.Manager) (*audit.Auditor, error) {
+	sinkNames := utils.SplitCommaSeparated(os.Getenv(common.AuditSinksEnvVarKey))
+	if len(sinkNames) == 0 {
+		return nil, nil
+	}
+
+	var sinks []audit.Sink
+	for _, sinkName := range sinkNames {
+		switch sinkName {
+		case audit.SinkStdout:
+			sinks = append(sinks, audit.NewWriterSink(os.Stdout))
+		case audit.SinkFile:
+			path := os.Getenv(common.AuditFilePathEnvVarKey)
+			if path == "" {
+				path = audit.DefaultFilePath
+			}
+			maxSizeMB, err := intFromEnv(common.AuditFileMaxSizeEnvVarKey, audit.DefaultFileMaxSizeMB)
+			if err != nil {
+				return nil, err
+			}
+			maxBackups, err := intFromEnv(common.AuditFileMaxBackupsEnvVarKey, audit.DefaultFileMaxBackups)
+			if err != nil {
+				return nil, err
+			}
+			sink, err := audit.NewFileSink(path, int64(maxSizeMB)*1024*1024, maxBackups)
+			if err != nil {
+				return nil, err
+			}
+			sinks = append(sinks, sink)
+		case audit.SinkHTTP:
+			endpoint := os.Getenv(common.AuditHTTPEndpointEnvVarKey)
+			if endpoint == "" {
+				return nil, fmt.Errorf("%s must be set for the %s audit sink", common.AuditHTTPEndpointEnvVarKey, audit.SinkHTTP)
+			}
+			batchSize, err := intFromEnv(common.AuditHTTPBatchSizeEnvVarKey, audit.DefaultHTTPBatchSize)
+			if err != nil {
+				return nil, err
+			}
+			flushInterval, err := durationFromEnv(common.AuditHTTPFlushIntervalEnvVarKey, audit.DefaultHTTPFlushInterval)
+			if err != nil {
+				return nil, err
+			}
+			maxRetries, err := intFromEnv(common.AuditHTTPMaxRetriesEnvVarKey, audit.DefaultHTTPMaxRetries)
+			if err != nil {
+				return nil, err
+			}
+			sink := audit.NewHTTPSink(endpoint, batchSize, flushInterval, maxRetries)
+			if err := mgr.Add(sink); err != nil {
+				return nil, err
+			}
+			sinks = append(sinks, sink)
+		default:
+			return nil, fmt.Errorf("invalid audit sink %q in %s", sinkName, common.AuditSinksEnvVarKey)
+		}
+	}
+
+	return audit.NewAuditor(sinks...), nil
+}
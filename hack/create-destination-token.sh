@@ -3,55 +3,106 @@ SOURCE_CLUSTER=$1
 DESTINATION_CLUSTER=$2
 NAMESPACE=$3
 SERVICE_ACCOUNT_NAME=$4
+BOOTSTRAP_SERVICE_ACCOUNT_NAME=$SERVICE_ACCOUNT_NAME-bootstrap
 
 kubectl config use-context $DESTINATION_CLUSTER
 kubectl create namespace $NAMESPACE --ignore-already-exists
 kubectl create serviceaccount $SERVICE_ACCOUNT_NAME -n $NAMESPACE
+kubectl create serviceaccount $BOOTSTRAP_SERVICE_ACCOUNT_NAME -n $NAMESPACE
 
+# Remove the cluster-admin binding created by previous versions of this script.
+kubectl delete clusterrolebinding $SERVICE_ACCOUNT_NAME-binding --ignore-not-found
 
+# The validator ServiceAccount only needs to review the access of the Argo instance admins.
 cat <<EOF | kubectl apply -f -
 apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRole
+metadata:
+  name: $SERVICE_ACCOUNT_NAME-access-reviewer
+rules:
+- apiGroups:
+  - authorization.k8s.io
+  resources:
+  - subjectaccessreviews
+  verbs:
+  - create
+---
+apiVersion: rbac.authorization.k8s.io/v1
 kind: ClusterRoleBinding
 metadata:
-  name: $SERVICE_ACCOUNT_NAME-binding
+  name: $SERVICE_ACCOUNT_NAME-access-reviewer
 roleRef:
   apiGroup: rbac.authorization.k8s.io
   kind: ClusterRole
-  name: cluster-admin
+  name: $SERVICE_ACCOUNT_NAME-access-reviewer
 subjects:
 - kind: ServiceAccount
   name: $SERVICE_ACCOUNT_NAME
   namespace: $NAMESPACE
 EOF
 
+# The bootstrap ServiceAccount may only mint tokens for the validator ServiceAccount.
+cat <<EOF | kubectl apply -f -
+apiVersion: rbac.authorization.k8s.io/v1
+kind: Role
+metadata:
+  name: $SERVICE_ACCOUNT_NAME-token-minter
+  namespace: $NAMESPACE
+rules:
+- apiGroups:
+  - ""
+  resources:
+  - serviceaccounts/token
+  resourceNames:
+  - $SERVICE_ACCOUNT_NAME
+  verbs:
+  - create
+---
+apiVersion: rbac.authorization.k8s.io/v1
+kind: RoleBinding
+metadata:
+  name: $SERVICE_ACCOUNT_NAME-token-minter
+  namespace: $NAMESPACE
+roleRef:
+  apiGroup: rbac.authorization.k8s.io
+  kind: Role
+  name: $SERVICE_ACCOUNT_NAME-token-minter
+subjects:
+- kind: ServiceAccount
+  name: $BOOTSTRAP_SERVICE_ACCOUNT_NAME
+  namespace: $NAMESPACE
+EOF
+
 cat <<EOF | kubectl apply -f -
 apiVersion: v1
 kind: Secret
 metadata:
-  name: $SERVICE_ACCOUNT_NAME-secret
+  name: $BOOTSTRAP_SERVICE_ACCOUNT_NAME-secret
   namespace: $NAMESPACE
   annotations:
-    kubernetes.io/service-account.name: $SERVICE_ACCOUNT_NAME
+    kubernetes.io/service-account.name: $BOOTSTRAP_SERVICE_ACCOUNT_NAME
 type: kubernetes.io/service-account-token
 EOF
 
 # Wait for the secret to be created
 echo "Waiting for the secret to be created..."
 sleep 3
-TOKEN=$(kubectl get secret -n $NAMESPACE $SERVICE_ACCOUNT_NAME-secret -oyaml | yq .data.token | base64 --decode)
+TOKEN=$(kubectl get secret -n $NAMESPACE $BOOTSTRAP_SERVICE_ACCOUNT_NAME-secret -oyaml | yq .data.token | base64 --decode)
+CA_CRT=$(kubectl get secret -n $NAMESPACE $BOOTSTRAP_SERVICE_ACCOUNT_NAME-secret -oyaml | yq '.data["ca.crt"]' | base64 --decode)
+
+SERVER=$(kubectl config view --minify --context $DESTINATION_CLUSTER -o jsonpath='{.clusters[0].cluster.server}')
+SERVER_HASH=$(echo -n "${SERVER%/}" | sha256sum | cut -c1-63)
 
-kubectl config use-context
+kubectl config use-context $SOURCE_CLUSTER
 if kubectl get namespace application-rbac-validator-system; then
   echo "Namespace application-rbac-validator-system already exists."
 else
   kubectl create namespace application-rbac-validator-system
 fi
-if kubectl get configmap -n application-rbac-validator-system application-rbac-validator-cluster-tokens; then
-  echo "Configmap application-rbac-validator-cluster-tokens already exists."
-else
-  kubectl create configmap -n application-rbac-validator-system application-rbac-validator-cluster-tokens
-fi
-kubectl patch configmap -n application-rbac-validator-system application-rbac-validator-cluster-tokens --patch "{\"data\":{ \"${DESTINATION_CLUSTER}-token\": \"${TOKEN}\" }}" --type=merge
-echo "Token for service account $SERVICE_ACCOUNT_NAME in cluster $DESTINATION_CLUSTER created and added to configmap."
-echo "Token is $TOKEN"
-
+kubectl create secret generic -n application-rbac-validator-system application-rbac-validator-cluster-${SERVER_HASH:0:10} \
+  --from-literal=server="$SERVER" --from-literal=token="$TOKEN" --from-literal=ca.crt="$CA_CRT" \
+  --from-literal=serviceAccount="$NAMESPACE/$SERVICE_ACCOUNT_NAME" \
+  --dry-run=client -o yaml | kubectl apply -f -
+kubectl label secret -n application-rbac-validator-system application-rbac-validator-cluster-${SERVER_HASH:0:10} \
+  argocd.dana.io/cluster-credentials=true argocd.dana.io/server-hash=$SERVER_HASH --overwrite
+echo "Bootstrap credentials minting tokens for service account $SERVICE_ACCOUNT_NAME in cluster $DESTINATION_CLUSTER ($SERVER) stored in a Secret."
@@ -0,0 +1,122 @@
+package audit
+
+import (
+	"context"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+)
+
+const (
+	// SinkStdout writes the audit records to the standard output.
+	SinkStdout = "stdout"
+	// SinkFile writes the audit records to a rotated file.
+	SinkFile = "file"
+	// SinkHTTP sends batches of audit records to an HTTP endpoint.
+	SinkHTTP = "http"
+)
+
+const (
+	// BypassLabel is the bypass of a validation skipped by a bypass label.
+	BypassLabel = "label"
+	// BypassGrant is the bypass of a validation skipped by a BypassGrant.
+	BypassGrant = "grant"
+	// BypassManagement is the bypass of a validation skipped for a management Application.
+	BypassManagement = "management"
+)
+
+// Destination is a destination of the audited object.
+type Destination struct {
+	Server    string `json:"server,omitempty"`
+	Name      string `json:"name,omitempty"`
+	Namespace string `json:"namespace,omitempty"`
+}
+
+// Record is the audit record of the validation of an admission request.
+type Record struct {
+	Timestamp    time.Time          `json:"timestamp"`
+	UID          string             `json:"uid,omitempty"`
+	Operation    string             `json:"operation,omitempty"`
+	User         string             `json:"user,omitempty"`
+	Groups       []string           `json:"groups,omitempty"`
+	Kind         string             `json:"kind"`
+	Namespace    string             `json:"namespace"`
+	Name         string             `json:"name"`
+	Destinations []Destination      `json:"destinations,omitempty"`
+	Clusters     []string           `json:"clusters,omitempty"`
+	Instance     string             `json:"instance,omitempty"`
+	Decision     string             `json:"decision"`
+	Reason       string             `json:"reason,omitempty"`
+	Message      string             `json:"message,omitempty"`
+	Bypass       string             `json:"bypass,omitempty"`
+	BypassGrant  string             `json:"bypassGrant,omitempty"`
+	Stages       map[string]float64 `json:"stageDurationsSeconds,omitempty"`
+}
+
+// SetBypass records that the validation was skipped by the given bypass, naming the BypassGrant if one was used.
+// It does nothing on a nil Record.
+func (r *Record) SetBypass(bypass, grant string) {
+	if r == nil {
+		return
+	}
+	r.Bypass = bypass
+	r.BypassGrant = grant
+}
+
+// ObserveStage adds the given duration to the time spent in the given validation stage. It does nothing on a nil
+// Record.
+func (r *Record) ObserveStage(stage string, duration time.Duration) {
+	if r == nil {
+		return
+	}
+	if r.Stages == nil {
+		r.Stages = map[string]float64{}
+	}
+	r.Stages[stage] += duration.Seconds()
+}
+
+// recordKey is the context key of the audit record of an admission request.
+type recordKey struct{}
+
+// NewContext returns a copy of the given context carrying the given audit record.
+func NewContext(ctx context.Context, record *Record) context.Context {
+	return context.WithValue(ctx, recordKey{}, record)
+}
+
+// FromContext returns the audit record carried by the given context, or nil if it carries none.
+func FromContext(ctx context.Context) *Record {
+	record, _ := ctx.Value(recordKey{}).(*Record)
+	return record
+}
+
+// Sink persists audit records.
+type Sink interface {
+	// Write persists the given record. It must not block on remote endpoints.
+	Write(record *Record) error
+}
+
+// Auditor emits the audit records of the validation decisions to its sinks.
+type Auditor struct {
+	sinks []Sink
+}
+
+// NewAuditor returns an Auditor emitting to the given sinks.
+func NewAuditor(sinks ...Sink) *Auditor {
+	return &Auditor{sinks: sinks}
+}
+
+// Emit writes the given record to every sink, logging the sinks that fail. It does nothing on a nil Auditor or for
+// a nil record.
+func (a *Auditor) Emit(record *Record) {
+	if a == nil || record == nil {
+		return
+	}
+
+	logger := zap.New().WithName("audit")
+	for _, sink := range a.sinks {
+		if err := sink.Write(record); err != nil {
+			logger.Error(err, "Failed to write audit record", "uid", record.UID, "kind", record.Kind,
+				"namespace", record.Namespace, "name", record.Name)
+		}
+	}
+}
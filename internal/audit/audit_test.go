@@ -0,0 +1,130 @@
+package audit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestWriterSink(t *testing.T) {
+	var buffer bytes.Buffer
+	auditor := NewAuditor(NewWriterSink(&buffer))
+
+	record := &Record{UID: "uid-1", Kind: "Application", Namespace: "argocd-team-a", Name: "app", Decision: "denied"}
+	record.SetBypass(BypassGrant, "grant")
+	record.ObserveStage("token_fetch", time.Second)
+	record.ObserveStage("token_fetch", time.Second)
+	auditor.Emit(record)
+
+	decoded := Record{}
+	if err := json.Unmarshal(buffer.Bytes(), &decoded); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if decoded.UID != "uid-1" || decoded.Bypass != BypassGrant || decoded.BypassGrant != "grant" {
+		t.Errorf("expected the record to be written but got %v", decoded)
+	}
+	if decoded.Stages["token_fetch"] != 2 {
+		t.Errorf("expected %v but got %v", 2, decoded.Stages["token_fetch"])
+	}
+}
+
+func TestFileSinkRotation(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit", "audit.log")
+	sink, err := NewFileSink(path, 150, 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	t.Cleanup(func() { _ = sink.Close() })
+
+	for _, uid := range []string{"uid-1", "uid-2", "uid-3", "uid-4"} {
+		if err := sink.Write(&Record{UID: uid, Kind: "Application", Name: "app"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	expected := map[string]string{path: "uid-4", path + ".1": "uid-3", path + ".2": "uid-2"}
+	for file, uid := range expected {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !strings.Contains(string(content), uid) || strings.Count(string(content), "\n") != 1 {
+			t.Errorf("expected %s to hold only record %s but got %s", file, uid, content)
+		}
+	}
+	if _, err := os.Stat(path + ".3"); !os.IsNotExist(err) {
+		t.Errorf("expected only 2 backups to be kept")
+	}
+}
+
+func TestHTTPSink(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		attempts int
+		received []string
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		attempts++
+		if attempts == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		var batch []Record
+		if err := json.NewDecoder(r.Body).Decode(&batch); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		for _, record := range batch {
+			received = append(received, record.UID)
+		}
+	}))
+	defer server.Close()
+
+	sink := NewHTTPSink(server.URL, 2, time.Hour, 1)
+	sink.RetryBackoff = time.Millisecond
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		_ = sink.Start(ctx)
+		close(done)
+	}()
+
+	for _, uid := range []string{"uid-1", "uid-2", "uid-3"} {
+		if err := sink.Write(&Record{UID: uid}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		mu.Lock()
+		batchSent := len(received) == 2
+		mu.Unlock()
+		if batchSent || time.Now().After(deadline) {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	cancel()
+	<-done
+
+	mu.Lock()
+	defer mu.Unlock()
+	if strings.Join(received, ",") != "uid-1,uid-2,uid-3" {
+		t.Errorf("expected the full batch to be retried and the rest flushed on shutdown but got %v", received)
+	}
+}
@@ -0,0 +1,147 @@
+package audit
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+const (
+	// DefaultFilePath is the path of the audit file.
+	DefaultFilePath = "/tmp/audit/audit.log"
+	// DefaultFileMaxSizeMB is the size in megabytes the audit file is rotated at.
+	DefaultFileMaxSizeMB = 100
+	// DefaultFileMaxBackups is the number of rotated audit files kept.
+	DefaultFileMaxBackups = 5
+)
+
+// WriterSink writes every audit record as a line of JSON to a writer.
+type WriterSink struct {
+	mu      sync.Mutex
+	encoder *json.Encoder
+}
+
+// NewWriterSink returns a WriterSink writing to the given writer.
+func NewWriterSink(w io.Writer) *WriterSink {
+	return &WriterSink{encoder: json.NewEncoder(w)}
+}
+
+// Write implements Sink.
+func (s *WriterSink) Write(record *Record) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.encoder.Encode(record)
+}
+
+// FileSink writes every audit record as a line of JSON to a file. The file is rotated once it would exceed its
+// maximum size, keeping up to a maximum number of backups named "<path>.1" (the newest) to "<path>.<maxBackups>".
+type FileSink struct {
+	path       string
+	maxSize    int64
+	maxBackups int
+
+	mu   sync.Mutex
+	file *os.File
+	size int64
+}
+
+// NewFileSink returns a FileSink appending to the file at the given path, creating it and its directory if needed.
+func NewFileSink(path string, maxSize int64, maxBackups int) (*FileSink, error) {
+	s := &FileSink{path: path, maxSize: maxSize, maxBackups: maxBackups}
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return nil, fmt.Errorf("failed to create the directory of audit file %s: %w", path, err)
+	}
+	if err := s.open(); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// Write implements Sink.
+func (s *FileSink) Write(record *Record) error {
+	line, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to marshal audit record: %w", err)
+	}
+	line = append(line, '\n')
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.size > 0 && s.size+int64(len(line)) > s.maxSize {
+		if err := s.rotate(); err != nil {
+			return err
+		}
+	}
+
+	n, err := s.file.Write(line)
+	s.size += int64(n)
+	if err != nil {
+		return fmt.Errorf("failed to write audit file %s: %w", s.path, err)
+	}
+
+	return nil
+}
+
+// Close closes the audit file.
+func (s *FileSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.file.Close()
+}
+
+// open opens the audit file for appending.
+func (s *FileSink) open() error {
+	file, err := os.OpenFile(s.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o640)
+	if err != nil {
+		return fmt.Errorf("failed to open audit file %s: %w", s.path, err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return fmt.Errorf("failed to stat audit file %s: %w", s.path, err)
+	}
+
+	s.file = file
+	s.size = info.Size()
+
+	return nil
+}
+
+// rotate shifts the backups of the audit file, dropping the oldest one, moves the audit file to the newest backup
+// and opens a new audit file.
+func (s *FileSink) rotate() error {
+	if err := s.file.Close(); err != nil {
+		return fmt.Errorf("failed to close audit file %s: %w", s.path, err)
+	}
+
+	for i := s.maxBackups - 1; i >= 1; i-- {
+		if err := os.Rename(s.backupPath(i), s.backupPath(i+1)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("failed to rotate audit file %s: %w", s.path, err)
+		}
+	}
+
+	var err error
+	if s.maxBackups > 0 {
+		err = os.Rename(s.path, s.backupPath(1))
+	} else {
+		err = os.Remove(s.path)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to rotate audit file %s: %w", s.path, err)
+	}
+
+	return s.open()
+}
+
+// backupPath returns the path of the given backup of the audit file.
+func (s *FileSink) backupPath(i int) string {
+	return fmt.Sprintf("%s.%d", s.path, i)
+}
@@ -0,0 +1,159 @@
+package audit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+)
+
+const (
+	// DefaultHTTPBatchSize is the number of audit records sent in a single request.
+	DefaultHTTPBatchSize = 100
+	// DefaultHTTPFlushInterval is the longest an audit record waits before its batch is sent.
+	DefaultHTTPFlushInterval = 5 * time.Second
+	// DefaultHTTPMaxRetries is the number of times sending a batch is retried before it is dropped.
+	DefaultHTTPMaxRetries = 3
+	// httpRequestTimeout is the timeout of a single request to the audit endpoint.
+	httpRequestTimeout = 10 * time.Second
+	// httpBufferedBatches is the number of batches buffered while the audit endpoint is unavailable.
+	httpBufferedBatches = 10
+)
+
+// HTTPSink sends the audit records to an HTTP endpoint as JSON arrays, in batches sent once they are full or once
+// the flush interval has passed. Failed batches are retried with an exponential backoff and dropped once the
+// retries are exhausted. Records written while the buffer is full are dropped.
+type HTTPSink struct {
+	Endpoint      string
+	Client        *http.Client
+	BatchSize     int
+	FlushInterval time.Duration
+	MaxRetries    int
+	RetryBackoff  time.Duration
+
+	records chan *Record
+}
+
+// NewHTTPSink returns an HTTPSink sending to the given endpoint.
+func NewHTTPSink(endpoint string, batchSize int, flushInterval time.Duration, maxRetries int) *HTTPSink {
+	return &HTTPSink{
+		Endpoint:      endpoint,
+		Client:        &http.Client{Timeout: httpRequestTimeout},
+		BatchSize:     batchSize,
+		FlushInterval: flushInterval,
+		MaxRetries:    maxRetries,
+		RetryBackoff:  time.Second,
+		records:       make(chan *Record, batchSize*httpBufferedBatches),
+	}
+}
+
+// Write implements Sink by buffering the record until its batch is sent.
+func (s *HTTPSink) Write(record *Record) error {
+	select {
+	case s.records <- record:
+		return nil
+	default:
+		return fmt.Errorf("audit buffer of %s is full, dropping the record", s.Endpoint)
+	}
+}
+
+// Start sends the buffered records in batches until the context is done, and then sends the remaining records.
+// It implements manager.Runnable.
+func (s *HTTPSink) Start(ctx context.Context) error {
+	ticker := time.NewTicker(s.FlushInterval)
+	defer ticker.Stop()
+
+	batch := make([]*Record, 0, s.BatchSize)
+	for {
+		select {
+		case <-ctx.Done():
+			for {
+				select {
+				case record := <-s.records:
+					batch = append(batch, record)
+				default:
+					flushCtx, cancel := context.WithTimeout(context.Background(), httpRequestTimeout)
+					s.flush(flushCtx, batch)
+					cancel()
+					return nil
+				}
+			}
+		case record := <-s.records:
+			batch = append(batch, record)
+			if len(batch) >= s.BatchSize {
+				s.flush(ctx, batch)
+				batch = batch[:0]
+			}
+		case <-ticker.C:
+			s.flush(ctx, batch)
+			batch = batch[:0]
+		}
+	}
+}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable, as every replica serves admission requests.
+func (s *HTTPSink) NeedLeaderElection() bool {
+	return false
+}
+
+// flush sends the given batch, retrying with an exponential backoff, and logs it when it is dropped.
+func (s *HTTPSink) flush(ctx context.Context, batch []*Record) {
+	if len(batch) == 0 {
+		return
+	}
+
+	body, err := json.Marshal(batch)
+	if err != nil {
+		zap.New().WithName("audit").Error(err, "Failed to marshal audit records, dropping them", "records", len(batch))
+		return
+	}
+
+	backoff := s.RetryBackoff
+	for attempt := 0; ; attempt++ {
+		if err = s.send(ctx, body); err == nil {
+			return
+		}
+		if attempt >= s.MaxRetries || !wait(ctx, backoff) {
+			break
+		}
+		backoff *= 2
+	}
+
+	zap.New().WithName("audit").Error(err, "Failed to send audit records, dropping them", "endpoint", s.Endpoint,
+		"records", len(batch))
+}
+
+// send posts the given JSON body to the audit endpoint.
+func (s *HTTPSink) send(ctx context.Context, body []byte) error {
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, s.Endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to build audit request: %w", err)
+	}
+	request.Header.Set("Content-Type", "application/json")
+
+	response, err := s.Client.Do(request)
+	if err != nil {
+		return fmt.Errorf("failed to send audit records to %s: %w", s.Endpoint, err)
+	}
+	defer func() { _ = response.Body.Close() }()
+
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		return fmt.Errorf("audit endpoint %s responded with status %d", s.Endpoint, response.StatusCode)
+	}
+
+	return nil
+}
+
+// wait waits for the given duration, returning false if the context is done first.
+func wait(ctx context.Context, duration time.Duration) bool {
+	select {
+	case <-ctx.Done():
+		return false
+	case <-time.After(duration):
+		return true
+	}
+}
@@ -0,0 +1,192 @@
+package clusterclient
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/dana-team/application-rbac-validator/internal/tracing"
+	"github.com/dana-team/application-rbac-validator/internal/utils"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+)
+
+// DefaultIdleTimeout is the duration after which an unused destination cluster client is evicted from the pool.
+const DefaultIdleTimeout = 10 * time.Minute
+
+// Provider provides clients for destination clusters.
+type Provider interface {
+	// ClientFor returns a client for the given destination server, using the given credentials.
+	ClientFor(server string, credentials utils.ClusterCredentials) (kubernetes.Interface, error)
+}
+
+// staticProvider is a Provider that always returns the same client.
+type staticProvider struct {
+	client kubernetes.Interface
+}
+
+// NewStaticProvider returns a Provider that returns the given client for every destination cluster.
+func NewStaticProvider(client kubernetes.Interface) Provider {
+	return &staticProvider{client: client}
+}
+
+// ClientFor implements Provider.
+func (p *staticProvider) ClientFor(_ string, _ utils.ClusterCredentials) (kubernetes.Interface, error) {
+	return p.client, nil
+}
+
+// entry is a pooled destination cluster client.
+type entry struct {
+	client         kubernetes.Interface
+	httpClient     *http.Client
+	credentialHash string
+	lastUsed       time.Time
+}
+
+// Pool is a Provider that reuses the clients, and with them the connections, of destination clusters across
+// admission requests. Clients are keyed by server and credential hash, so a client is rebuilt once the credentials
+// of its server change, and clients that were not used for the idle timeout are evicted.
+type Pool struct {
+	mu          sync.Mutex
+	entries     map[string]*entry
+	idleTimeout time.Duration
+	now         func() time.Time
+	build       func(server string, credentials utils.ClusterCredentials) (kubernetes.Interface, *http.Client, error)
+}
+
+// NewPool returns an empty Pool evicting clients that were not used for the given idle timeout.
+func NewPool(idleTimeout time.Duration) *Pool {
+	return &Pool{
+		entries:     map[string]*entry{},
+		idleTimeout: idleTimeout,
+		now:         time.Now,
+		build:       buildClient,
+	}
+}
+
+// buildClient builds a client for the destination cluster along with the HTTP client it uses.
+func buildClient(server string, credentials utils.ClusterCredentials) (kubernetes.Interface, *http.Client, error) {
+	config := utils.BuildClusterRestConfig(server, credentials)
+	config.Wrap(tracing.WrapTransport)
+	httpClient, err := rest.HTTPClientFor(config)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to build HTTP client for %s: %w", server, err)
+	}
+
+	client, err := kubernetes.NewForConfigAndClient(config, httpClient)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to build client for %s: %w", server, err)
+	}
+
+	return client, httpClient, nil
+}
+
+// hashCredentials returns the hash credentials are stored under, so tokens are not kept in the pool's keys.
+func hashCredentials(credentials utils.ClusterCredentials) string {
+	hash := sha256.New()
+	hash.Write([]byte(credentials.Token))
+	hash.Write([]byte{0})
+	hash.Write(credentials.CertData)
+	hash.Write([]byte{0})
+	hash.Write(credentials.KeyData)
+	hash.Write([]byte{0})
+	hash.Write(credentials.CAData)
+	hash.Write([]byte{0})
+	hash.Write([]byte(strconv.FormatBool(credentials.Insecure)))
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// ClientFor implements Provider. It returns the pooled client of the server if it was built with the same
+// credentials, and builds and pools a new one otherwise.
+func (p *Pool) ClientFor(server string, credentials utils.ClusterCredentials) (kubernetes.Interface, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := p.now()
+	p.evictIdleLocked(now)
+
+	credentialHash := hashCredentials(credentials)
+	if cached, ok := p.entries[server]; ok {
+		if cached.credentialHash == credentialHash {
+			cached.lastUsed = now
+			return cached.client, nil
+		}
+		p.removeLocked(server)
+	}
+
+	client, httpClient, err := p.build(server, credentials)
+	if err != nil {
+		return nil, err
+	}
+
+	p.entries[server] = &entry{
+		client:         client,
+		httpClient:     httpClient,
+		credentialHash: credentialHash,
+		lastUsed:       now,
+	}
+
+	return client, nil
+}
+
+// Len returns the number of pooled clients.
+func (p *Pool) Len() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return len(p.entries)
+}
+
+// EvictIdle evicts the clients that were not used for the idle timeout.
+func (p *Pool) EvictIdle() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.evictIdleLocked(p.now())
+}
+
+// evictIdleLocked evicts the clients that were not used for the idle timeout. The caller must hold the lock.
+func (p *Pool) evictIdleLocked(now time.Time) {
+	for server, cached := range p.entries {
+		if now.Sub(cached.lastUsed) > p.idleTimeout {
+			p.removeLocked(server)
+		}
+	}
+}
+
+// removeLocked removes the client of the server and closes its idle connections. The caller must hold the lock.
+func (p *Pool) removeLocked(server string) {
+	if cached, ok := p.entries[server]; ok && cached.httpClient != nil {
+		cached.httpClient.CloseIdleConnections()
+	}
+	delete(p.entries, server)
+}
+
+// Start periodically evicts idle clients until the context is done. It implements manager.Runnable.
+func (p *Pool) Start(ctx context.Context) error {
+	logger := zap.New().WithName("clusterclient")
+
+	ticker := time.NewTicker(p.idleTimeout)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			p.EvictIdle()
+			logger.V(1).Info("Evicted idle destination cluster clients", "pooled", p.Len())
+		}
+	}
+}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable, as every replica serves admission requests.
+func (p *Pool) NeedLeaderElection() bool {
+	return false
+}
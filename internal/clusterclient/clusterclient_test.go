@@ -0,0 +1,120 @@
+package clusterclient
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/dana-team/application-rbac-validator/internal/utils"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+const (
+	sampleServer      = "https://api.my-cluster.example.com:6443"
+	otherSampleServer = "https://api.other-cluster.example.com:6443"
+	sampleToken       = "token"
+)
+
+// newTestPool returns a pool with a controllable clock that counts the clients it builds.
+func newTestPool(now *time.Time, builds *int) *Pool {
+	pool := NewPool(time.Minute)
+	pool.now = func() time.Time { return *now }
+	pool.build = func(_ string, _ utils.ClusterCredentials) (kubernetes.Interface, *http.Client, error) {
+		*builds++
+		return fake.NewClientset(), nil, nil
+	}
+	return pool
+}
+
+func TestPoolClientFor(t *testing.T) {
+	testCases := []struct {
+		name           string
+		requests       []struct{ server, token string }
+		advance        time.Duration
+		expectedBuilds int
+		expectedLen    int
+	}{
+		{
+			name: "should reuse the client of a server",
+			requests: []struct{ server, token string }{
+				{sampleServer, sampleToken},
+				{sampleServer, sampleToken},
+			},
+			expectedBuilds: 1,
+			expectedLen:    1,
+		},
+		{
+			name: "should rebuild the client when the token changes",
+			requests: []struct{ server, token string }{
+				{sampleServer, sampleToken},
+				{sampleServer, "rotated-token"},
+			},
+			expectedBuilds: 2,
+			expectedLen:    1,
+		},
+		{
+			name: "should keep a client per server",
+			requests: []struct{ server, token string }{
+				{sampleServer, sampleToken},
+				{otherSampleServer, sampleToken},
+			},
+			expectedBuilds: 2,
+			expectedLen:    2,
+		},
+		{
+			name: "should rebuild clients that were evicted for being idle",
+			requests: []struct{ server, token string }{
+				{sampleServer, sampleToken},
+				{sampleServer, sampleToken},
+			},
+			advance:        2 * time.Minute,
+			expectedBuilds: 2,
+			expectedLen:    1,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			now := time.Now()
+			builds := 0
+			pool := newTestPool(&now, &builds)
+
+			for _, request := range tc.requests {
+				if _, err := pool.ClientFor(request.server, utils.ClusterCredentials{Token: request.token}); err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				now = now.Add(tc.advance)
+			}
+
+			if builds != tc.expectedBuilds {
+				t.Errorf("expected %d builds but got %d", tc.expectedBuilds, builds)
+			}
+			if pool.Len() != tc.expectedLen {
+				t.Errorf("expected %d pooled clients but got %d", tc.expectedLen, pool.Len())
+			}
+		})
+	}
+}
+
+func TestPoolEvictIdle(t *testing.T) {
+	now := time.Now()
+	builds := 0
+	pool := newTestPool(&now, &builds)
+
+	if _, err := pool.ClientFor(sampleServer, utils.ClusterCredentials{Token: sampleToken}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	now = now.Add(30 * time.Second)
+	pool.EvictIdle()
+	if pool.Len() != 1 {
+		t.Errorf("expected the recently used client to be kept but got %d pooled clients", pool.Len())
+	}
+
+	now = now.Add(2 * time.Minute)
+	pool.EvictIdle()
+	if pool.Len() != 0 {
+		t.Errorf("expected the idle client to be evicted but got %d pooled clients", pool.Len())
+	}
+}
@@ -1,22 +1,79 @@
 package common
 
 const (
-	ClusterTokensConfigMapName       = "application-rbac-validator-cluster-tokens"
-	ArgoInstanceConfigMapName        = "argo-config"
-	ArgoInstanceUsersConfigMapKey    = "instance_users"
-	ArgoInstanceNameConfigMapKey     = "instance_name"
-	InstanceUsersAccessLevelResource = "pods"
-	AdminBypassLabel                 = "argocd.dana.io/bypass-rbac-validation"
-	BypassOptimizationLabel          = "argocd.dana.io/bypass-optimization"
-	DefaultServerUrlPort             = "6443"
-	NamespaceKey                     = "namespaces"
-	ClusterResourcesKey              = "clusterResources"
-	ClusterDomainEnvVarKey           = "KUBERNETES_CLUSTER_DOMAIN"
-	DefaultServerUrlDomain           = "cluster.local"
-	SecretNameSuffix                 = "cluster-secret"
-	ArgoCDSecretTypeLabelKey         = "argocd.argoproj.io/secret-type"
-	ArgoCDSecretTypeClusterValue     = "cluster"
-	LabelValueTrue                   = "true"
+	ClusterTokensConfigMapName        = "application-rbac-validator-cluster-tokens"
+	ArgoInstanceConfigMapName         = "argo-config"
+	ArgoInstanceUsersConfigMapKey     = "instance_users"
+	ArgoInstanceNameConfigMapKey      = "instance_name"
+	InstanceUsersAccessLevelResource  = "pods"
+	AdminBypassLabel                  = "argocd.dana.io/bypass-rbac-validation"
+	BypassOptimizationLabel           = "argocd.dana.io/bypass-optimization"
+	DefaultServerUrlPort              = "6443"
+	NamespaceKey                      = "namespaces"
+	ClusterResourcesKey               = "clusterResources"
+	ClusterDomainEnvVarKey            = "KUBERNETES_CLUSTER_DOMAIN"
+	DefaultServerUrlDomain            = "cluster.local"
+	SecretNameSuffix                  = "cluster-secret"
+	ArgoCDSecretTypeLabelKey          = "argocd.argoproj.io/secret-type"
+	ArgoCDSecretTypeClusterValue      = "cluster"
+	LabelValueTrue                    = "true"
+	InClusterServerUrl                = "https://kubernetes.default.svc"
+	EnforcementModeLabel              = "argocd.dana.io/enforcement-mode"
+	EnforcementModeEnvVarKey          = "ENFORCEMENT_MODE"
+	ClusterEnforcementModesEnvVarKey  = "CLUSTER_ENFORCEMENT_MODES"
+	EnforcementModeEnforce            = "enforce"
+	EnforcementModeWarn               = "warn"
+	SubjectKindUser                   = "user"
+	SubjectKindGroup                  = "group"
+	SubjectKindServiceAccount         = "serviceaccount"
+	ServiceAccountUsernamePrefix      = "system:serviceaccount:"
+	ServiceAccountsGroup              = "system:serviceaccounts"
+	PermissionProfilesConfigMapName   = "application-rbac-validator-permission-profiles"
+	PermissionProfilesConfigMapKey    = "profiles.yaml"
+	PermissionProfileConfigMapKey     = "permission_profile"
+	DefaultPermissionProfileName      = "admin"
+	ClusterScopedAccessLevelResource  = "namespaces"
+	ClusterScopedValidationEnvVarKey  = "CLUSTER_SCOPED_VALIDATION"
+	MaxConcurrentAccessReviews        = 10
+	ClusterTokenConfigMapKeySuffix    = "-token"
+	ClusterCAConfigMapKeySuffix       = "-ca.crt"
+	ClusterInsecureConfigMapKeySuffix = "-insecure"
+	ClusterSecretsNamespaceEnvVarKey  = "ARGOCD_CLUSTER_SECRETS_NAMESPACE"
+	ClusterCredentialsLabelKey        = "argocd.dana.io/cluster-credentials"
+	ClusterServerHashLabelKey         = "argocd.dana.io/server-hash"
+	ClusterCredentialsServerKey       = "server"
+	ClusterCredentialsTokenKey        = "token"
+	ClusterCredentialsCAKey           = "ca.crt"
+	ClusterCredentialsInsecureKey     = "insecure"
+	ClusterServiceAccountKey          = "serviceAccount"
+	LabelValueMaxLength               = 63
+	TokenProbeIntervalEnvVarKey       = "TOKEN_PROBE_INTERVAL"
+	TokenExpiryWarningEnvVarKey       = "TOKEN_EXPIRY_WARNING_THRESHOLD"
+	EventRecorderName                 = "application-rbac-validator"
+	TokenRequestExpirationEnvVarKey   = "TOKEN_REQUEST_EXPIRATION"
+	TokenRequestAudiencesEnvVarKey    = "TOKEN_REQUEST_AUDIENCES"
+	BypassGrantExpiryWarningEnvVarKey = "BYPASS_GRANT_EXPIRY_WARNING_THRESHOLD"
+	BypassGrantApproversEnvVarKey     = "BYPASS_GRANT_APPROVER_GROUPS"
+	BypassGrantMaxTTLEnvVarKey        = "BYPASS_GRANT_MAX_TTL"
+	AuditSinksEnvVarKey               = "AUDIT_SINKS"
+	AuditFilePathEnvVarKey            = "AUDIT_FILE_PATH"
+	AuditFileMaxSizeEnvVarKey         = "AUDIT_FILE_MAX_SIZE_MB"
+	AuditFileMaxBackupsEnvVarKey      = "AUDIT_FILE_MAX_BACKUPS"
+	AuditHTTPEndpointEnvVarKey        = "AUDIT_HTTP_ENDPOINT"
+	AuditHTTPBatchSizeEnvVarKey       = "AUDIT_HTTP_BATCH_SIZE"
+	AuditHTTPFlushIntervalEnvVarKey   = "AUDIT_HTTP_FLUSH_INTERVAL"
+	AuditHTTPMaxRetriesEnvVarKey      = "AUDIT_HTTP_MAX_RETRIES"
+	TracingOTLPEndpointEnvVarKey      = "TRACING_OTLP_ENDPOINT"
+	TracingOTLPInsecureEnvVarKey      = "TRACING_OTLP_INSECURE"
+	RevalidationIntervalEnvVarKey     = "REVALIDATION_INTERVAL"
+	RevalidationAutoSyncEnvVarKey     = "REVALIDATION_DISABLE_AUTO_SYNC"
+	ClusterSecretResyncEnvVarKey      = "CLUSTER_SECRET_RESYNC_PERIOD"
+	AppliedDestinationAnnotation      = "argocd.dana.io/applied-destination"
+	RevalidationStatusAnnotation      = "argocd.dana.io/revalidation-status"
+	RevalidationReasonAnnotation      = "argocd.dana.io/revalidation-reason"
+	RevalidationMessageAnnotation     = "argocd.dana.io/revalidation-message"
+	RevalidationStatusCompliant       = "Compliant"
+	RevalidationStatusNonCompliant    = "NonCompliant"
 )
 
 var (
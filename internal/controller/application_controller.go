@@ -23,8 +23,11 @@ import (
 	argoprojv1alpha1 "github.com/argoproj/argo-cd/v3/pkg/apis/application/v1alpha1"
 	"github.com/dana-team/application-rbac-validator/internal/handlers"
 	"github.com/dana-team/application-rbac-validator/internal/metrics"
+	"github.com/dana-team/application-rbac-validator/internal/tracing"
 	"github.com/dana-team/application-rbac-validator/internal/utils"
+	"go.opentelemetry.io/otel/attribute"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
@@ -36,7 +39,10 @@ import (
 type ApplicationReconciler struct {
 	client.Client
 	Scheme          *runtime.Scheme
+	Recorder        record.EventRecorder
 	NamespacePrefix string
+	// ServerUrlDomain is the domain used to resolve a destination written as a short cluster name.
+	ServerUrlDomain string
 }
 
 // +kubebuilder:rbac:groups=argoproj.io,resources=applications,verbs=get;list;watch;create;update;patch;delete
@@ -44,7 +50,11 @@ type ApplicationReconciler struct {
 // +kubebuilder:rbac:groups=argoproj.io,resources=applications/finalizers,verbs=update
 // +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch;update;patch
 
-func (r *ApplicationReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+func (r *ApplicationReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Result, err error) {
+	ctx, span := tracing.Start(ctx, "ApplicationReconciler.Reconcile", attribute.String("namespace", req.Namespace),
+		attribute.String("name", req.Name))
+	defer func() { tracing.End(span, err) }()
+
 	baseLogger := zap.New().WithName("controller")
 	app := &argoprojv1alpha1.Application{}
 	if err := r.Get(ctx, req.NamespacedName, app); err != nil {
@@ -66,7 +76,10 @@ func (r *ApplicationReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 	log := baseLogger.WithValues("app", app.Name, "destination", resolvedServer)
 	if utils.IsInCluster(resolvedServer) {
 		log.Info("application is targeting in-cluster, ignoring...", "app", app.Name)
-		metrics.ObserveApplicationOptimizationStatus(app.Name, app.Namespace, app.Spec.Destination.Namespace, resolvedServer, "in-cluster", false)
+		metrics.ObserveApplicationOptimizationStatus(app.Name, app.Namespace, app.Spec.Destination.Namespace, utils.DestinationKey(resolvedServer, r.ServerUrlDomain), "in-cluster", false)
+		if app.DeletionTimestamp.IsZero() {
+			return ctrl.Result{}, handlers.RetractPreviousDestination(log, ctx, r.Client, r.Recorder, app, resolvedServer, r.ServerUrlDomain)
+		}
 		return ctrl.Result{}, nil
 	}
 
@@ -75,10 +88,13 @@ func (r *ApplicationReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 	// Therefore, we skip processing applications that are being deleted.
 	if !app.DeletionTimestamp.IsZero() {
 		log.Info("application is being deleted, ignoring...", "app", app.Name)
-		metrics.DeleteApplicationOptimizationStatus(app.Name, app.Namespace, resolvedServer)
+		metrics.DeleteApplicationOptimizationStatus(app.Name, app.Namespace)
 		return ctrl.Result{}, nil
 	}
-	return ctrl.Result{}, handlers.HandleCreateOrUpdate(log, ctx, r.Client, app)
+	if err := handlers.HandleCreateOrUpdate(log, ctx, r.Client, r.Recorder, app, r.ServerUrlDomain); err != nil {
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{}, handlers.RetractPreviousDestination(log, ctx, r.Client, r.Recorder, app, resolvedServer, r.ServerUrlDomain)
 }
 
 // SetupWithManager sets up the controller with the Manager.
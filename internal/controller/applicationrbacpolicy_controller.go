@@ -0,0 +1,168 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	rbacv1alpha1 "github.com/dana-team/application-rbac-validator/api/v1alpha1"
+	"github.com/dana-team/application-rbac-validator/internal/common"
+	"github.com/dana-team/application-rbac-validator/internal/utils"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// ApplicationRBACPolicyReconciler reconciles an ApplicationRBACPolicy object, reporting whether it is valid.
+type ApplicationRBACPolicyReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+}
+
+// +kubebuilder:rbac:groups=argocd.dana.io,resources=applicationrbacpolicies,verbs=get;list;watch
+// +kubebuilder:rbac:groups=argocd.dana.io,resources=applicationrbacpolicies/status,verbs=get;update;patch
+
+func (r *ApplicationRBACPolicyReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := zap.New().WithName("controller").WithValues("applicationRBACPolicy", req.NamespacedName)
+	policy := &rbacv1alpha1.ApplicationRBACPolicy{}
+	if err := r.Get(ctx, req.NamespacedName, policy); err != nil {
+		if client.IgnoreNotFound(err) == nil {
+			return ctrl.Result{}, nil
+		}
+		log.Error(err, "unable to fetch ApplicationRBACPolicy")
+		return ctrl.Result{}, err
+	}
+
+	condition, err := r.readyCondition(ctx, policy)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	condition.ObservedGeneration = policy.Generation
+
+	meta.SetStatusCondition(&policy.Status.Conditions, condition)
+	policy.Status.ObservedGeneration = policy.Generation
+	if err := r.Status().Update(ctx, policy); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to update ApplicationRBACPolicy status: %w", err)
+	}
+
+	log.Info("Reconciled ApplicationRBACPolicy", "ready", condition.Status, "reason", condition.Reason)
+	return ctrl.Result{}, nil
+}
+
+// readyCondition returns the Ready condition of the given policy.
+func (r *ApplicationRBACPolicyReconciler) readyCondition(ctx context.Context, policy *rbacv1alpha1.ApplicationRBACPolicy) (metav1.Condition, error) {
+	policyList := &rbacv1alpha1.ApplicationRBACPolicyList{}
+	if err := r.List(ctx, policyList, client.InNamespace(policy.Namespace)); err != nil {
+		return metav1.Condition{}, fmt.Errorf("failed to list ApplicationRBACPolicies in namespace %s: %w", policy.Namespace, err)
+	}
+	if len(policyList.Items) > 1 {
+		return notReady(rbacv1alpha1.ReasonConflict, fmt.Sprintf(
+			"namespace %s holds %d ApplicationRBACPolicies, the webhook rejects its Applications until only one is left",
+			policy.Namespace, len(policyList.Items))), nil
+	}
+
+	if policy.Spec.PermissionProfile != "" {
+		currentNamespace, err := utils.GetCurrentNamespace()
+		if err != nil {
+			return metav1.Condition{}, fmt.Errorf("failed to fetch the webhook's current namespace name: %w", err)
+		}
+		config, err := utils.FetchPermissionProfilesConfig(ctx, r.Client, currentNamespace)
+		if err != nil {
+			return metav1.Condition{}, err
+		}
+		if _, err := config.Profile(policy.Spec.PermissionProfile); err != nil {
+			return notReady(rbacv1alpha1.ReasonPermissionProfileNotFound, err.Error()), nil
+		}
+	}
+
+	if err := utils.ValidateAllowedClusters(policy); err != nil {
+		return notReady(rbacv1alpha1.ReasonInvalidClusterPattern, err.Error()), nil
+	}
+
+	if err := utils.ValidateManagementApplications(policy); err != nil {
+		return notReady(rbacv1alpha1.ReasonInvalidManagementApplications, err.Error()), nil
+	}
+
+	return metav1.Condition{
+		Type:    rbacv1alpha1.ConditionTypeReady,
+		Status:  metav1.ConditionTrue,
+		Reason:  rbacv1alpha1.ReasonValid,
+		Message: "ApplicationRBACPolicy is valid",
+	}, nil
+}
+
+// notReady returns a Ready condition with status False and the given reason and message.
+func notReady(reason, message string) metav1.Condition {
+	return metav1.Condition{
+		Type:    rbacv1alpha1.ConditionTypeReady,
+		Status:  metav1.ConditionFalse,
+		Reason:  reason,
+		Message: message,
+	}
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *ApplicationRBACPolicyReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&rbacv1alpha1.ApplicationRBACPolicy{}).
+		Watches(&rbacv1alpha1.ApplicationRBACPolicy{},
+			handler.EnqueueRequestsFromMapFunc(r.policiesInNamespace),
+		).
+		Watches(&corev1.ConfigMap{},
+			handler.EnqueueRequestsFromMapFunc(r.policiesForPermissionProfiles),
+		).
+		Complete(r)
+}
+
+// policiesInNamespace enqueues every ApplicationRBACPolicy sharing the namespace of the given policy, since a
+// conflict between them is resolved once the others are deleted.
+func (r *ApplicationRBACPolicyReconciler) policiesInNamespace(ctx context.Context, o client.Object) []reconcile.Request {
+	return r.listPolicyRequests(ctx, client.InNamespace(o.GetNamespace()))
+}
+
+// policiesForPermissionProfiles enqueues every ApplicationRBACPolicy when the permission profiles ConfigMap changes,
+// since their permission profile may have been added or removed.
+func (r *ApplicationRBACPolicyReconciler) policiesForPermissionProfiles(ctx context.Context, o client.Object) []reconcile.Request {
+	if o.GetName() != common.PermissionProfilesConfigMapName {
+		return nil
+	}
+
+	return r.listPolicyRequests(ctx)
+}
+
+// listPolicyRequests returns a reconcile request for every ApplicationRBACPolicy matching the given options.
+func (r *ApplicationRBACPolicyReconciler) listPolicyRequests(ctx context.Context, opts ...client.ListOption) []reconcile.Request {
+	policyList := &rbacv1alpha1.ApplicationRBACPolicyList{}
+	if err := r.List(ctx, policyList, opts...); err != nil {
+		zap.New().WithName("controller").Error(err, "unable to list ApplicationRBACPolicies")
+		return nil
+	}
+
+	requests := make([]reconcile.Request, 0, len(policyList.Items))
+	for _, policy := range policyList.Items {
+		requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&policy)})
+	}
+
+	return requests
+}
@@ -0,0 +1,120 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	rbacv1alpha1 "github.com/dana-team/application-rbac-validator/api/v1alpha1"
+	"github.com/dana-team/application-rbac-validator/internal/metrics"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+)
+
+// DefaultBypassGrantExpiryWarningThreshold is how long before a BypassGrant expires it is reported as expiring soon.
+const DefaultBypassGrantExpiryWarningThreshold = 24 * time.Hour
+
+// BypassGrantReconciler reconciles a BypassGrant object, reporting when it is about to expire and once it expired.
+type BypassGrantReconciler struct {
+	client.Client
+	Scheme                 *runtime.Scheme
+	Recorder               record.EventRecorder
+	ExpiryWarningThreshold time.Duration
+
+	now func() time.Time
+}
+
+// +kubebuilder:rbac:groups=argocd.dana.io,resources=bypassgrants,verbs=get;list;watch
+// +kubebuilder:rbac:groups=argocd.dana.io,resources=bypassgrants/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+
+func (r *BypassGrantReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := zap.New().WithName("controller").WithValues("bypassGrant", req.NamespacedName)
+	grant := &rbacv1alpha1.BypassGrant{}
+	if err := r.Get(ctx, req.NamespacedName, grant); err != nil {
+		if client.IgnoreNotFound(err) == nil {
+			metrics.DeleteBypassGrantMetrics(req.Namespace, req.Name)
+			return ctrl.Result{}, nil
+		}
+		log.Error(err, "unable to fetch BypassGrant")
+		return ctrl.Result{}, err
+	}
+
+	expiresAt := grant.Spec.ExpiresAt.Time
+	remaining := expiresAt.Sub(r.currentTime())
+	condition := metav1.Condition{
+		Type:               rbacv1alpha1.ConditionTypeActive,
+		Status:             metav1.ConditionTrue,
+		Reason:             rbacv1alpha1.ReasonGrantActive,
+		Message:            fmt.Sprintf("BypassGrant expires at %s", expiresAt.Format(time.RFC3339)),
+		ObservedGeneration: grant.Generation,
+	}
+	var result ctrl.Result
+	switch {
+	case remaining <= 0:
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = rbacv1alpha1.ReasonGrantExpired
+		condition.Message = fmt.Sprintf("BypassGrant expired at %s and no longer bypasses validation",
+			expiresAt.Format(time.RFC3339))
+	case remaining <= r.ExpiryWarningThreshold:
+		condition.Reason = rbacv1alpha1.ReasonGrantExpiringSoon
+		result.RequeueAfter = remaining
+	default:
+		result.RequeueAfter = remaining - r.ExpiryWarningThreshold
+	}
+
+	metrics.DeleteBypassGrantMetrics(grant.Namespace, grant.Name)
+	metrics.ObserveBypassGrant(grant.Namespace, grant.Name, grant.Spec.Cluster, expiresAt,
+		condition.Reason == rbacv1alpha1.ReasonGrantExpiringSoon)
+
+	previous := meta.FindStatusCondition(grant.Status.Conditions, rbacv1alpha1.ConditionTypeActive)
+	if condition.Reason != rbacv1alpha1.ReasonGrantActive && (previous == nil || previous.Reason != condition.Reason) {
+		r.Recorder.Event(grant, corev1.EventTypeWarning, condition.Reason, condition.Message)
+	}
+
+	meta.SetStatusCondition(&grant.Status.Conditions, condition)
+	grant.Status.ObservedGeneration = grant.Generation
+	if err := r.Status().Update(ctx, grant); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to update BypassGrant status: %w", err)
+	}
+
+	log.Info("Reconciled BypassGrant", "active", condition.Status, "reason", condition.Reason)
+	return result, nil
+}
+
+// currentTime returns the current time, using the reconciler's clock when it is set.
+func (r *BypassGrantReconciler) currentTime() time.Time {
+	if r.now != nil {
+		return r.now()
+	}
+	return time.Now()
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *BypassGrantReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&rbacv1alpha1.BypassGrant{}).
+		Complete(r)
+}
@@ -0,0 +1,100 @@
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	rbacv1alpha1 "github.com/dana-team/application-rbac-validator/api/v1alpha1"
+	testutils "github.com/dana-team/application-rbac-validator/test/utils"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestBypassGrantReconcile(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name            string
+		expiresIn       time.Duration
+		expectedStatus  metav1.ConditionStatus
+		expectedReason  string
+		expectedRequeue time.Duration
+		expectEvent     bool
+	}{
+		{
+			name:            "should requeue an active grant when it starts expiring soon",
+			expiresIn:       72 * time.Hour,
+			expectedStatus:  metav1.ConditionTrue,
+			expectedReason:  rbacv1alpha1.ReasonGrantActive,
+			expectedRequeue: 48 * time.Hour,
+		},
+		{
+			name:            "should warn about a grant expiring soon and requeue it when it expires",
+			expiresIn:       time.Hour,
+			expectedStatus:  metav1.ConditionTrue,
+			expectedReason:  rbacv1alpha1.ReasonGrantExpiringSoon,
+			expectedRequeue: time.Hour,
+			expectEvent:     true,
+		},
+		{
+			name:           "should warn about an expired grant",
+			expiresIn:      -time.Hour,
+			expectedStatus: metav1.ConditionFalse,
+			expectedReason: rbacv1alpha1.ReasonGrantExpired,
+			expectEvent:    true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			grant := &rbacv1alpha1.BypassGrant{
+				ObjectMeta: metav1.ObjectMeta{Name: "grant", Namespace: "argocd-team-a"},
+				Spec: rbacv1alpha1.BypassGrantSpec{
+					Reason:    "migration",
+					Approver:  "platform-oncall",
+					ExpiresAt: metav1.NewTime(now.Add(tc.expiresIn)),
+				},
+			}
+			recorder := record.NewFakeRecorder(10)
+			reconciler := &BypassGrantReconciler{
+				Client:                 testutils.NewFakeClient(grant),
+				Recorder:               recorder,
+				ExpiryWarningThreshold: DefaultBypassGrantExpiryWarningThreshold,
+				now:                    func() time.Time { return now },
+			}
+			request := ctrl.Request{NamespacedName: client.ObjectKeyFromObject(grant)}
+
+			result, err := reconciler.Reconcile(context.Background(), request)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result.RequeueAfter != tc.expectedRequeue {
+				t.Errorf("expected requeue after %v but got %v", tc.expectedRequeue, result.RequeueAfter)
+			}
+
+			updated := &rbacv1alpha1.BypassGrant{}
+			if err := reconciler.Get(context.Background(), request.NamespacedName, updated); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			condition := meta.FindStatusCondition(updated.Status.Conditions, rbacv1alpha1.ConditionTypeActive)
+			if condition == nil {
+				t.Fatalf("expected an %s condition but got none", rbacv1alpha1.ConditionTypeActive)
+			}
+			if condition.Status != tc.expectedStatus || condition.Reason != tc.expectedReason {
+				t.Errorf("expected condition %s/%s but got %s/%s", tc.expectedStatus, tc.expectedReason,
+					condition.Status, condition.Reason)
+			}
+
+			if _, err := reconciler.Reconcile(context.Background(), request); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if events := len(recorder.Events); events != map[bool]int{true: 1, false: 0}[tc.expectEvent] {
+				t.Errorf("expected an Event only on the first transition but got %d Events", events)
+			}
+		})
+	}
+}
@@ -0,0 +1,116 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	argoprojv1alpha1 "github.com/argoproj/argo-cd/v3/pkg/apis/application/v1alpha1"
+	"github.com/dana-team/application-rbac-validator/internal/common"
+	"github.com/dana-team/application-rbac-validator/internal/handlers"
+	"github.com/dana-team/application-rbac-validator/internal/utils"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// DefaultClusterSecretResyncPeriod is the default period after which the namespaces of a cluster secret are
+// recomputed even if nothing changed.
+const DefaultClusterSecretResyncPeriod = 10 * time.Minute
+
+// ClusterSecretReconciler reconciles the cluster secrets of the Argo instance namespaces, recomputing their
+// namespaces from the live Applications targeting their cluster. Unlike the Application controller, it also runs when
+// Applications are deleted, so the namespaces they no longer need are removed.
+type ClusterSecretReconciler struct {
+	client.Client
+	Scheme          *runtime.Scheme
+	Recorder        record.EventRecorder
+	NamespacePrefix string
+	ResyncPeriod    time.Duration
+	// ServerUrlDomain is the domain used to resolve a destination written as a short cluster name.
+	ServerUrlDomain string
+}
+
+// +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch;update;patch
+// +kubebuilder:rbac:groups=argoproj.io,resources=applications,verbs=get;list;watch
+
+func (r *ClusterSecretReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := zap.New().WithName("controller").WithValues("clusterSecret", req.NamespacedName)
+	secret := &corev1.Secret{}
+	if err := r.Get(ctx, req.NamespacedName, secret); err != nil {
+		if client.IgnoreNotFound(err) == nil {
+			return ctrl.Result{}, nil
+		}
+		log.Error(err, "unable to fetch cluster secret")
+		return ctrl.Result{}, err
+	}
+
+	if err := handlers.SyncClusterSecretNamespaces(log, ctx, r.Client, r.Recorder, secret, r.ServerUrlDomain); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{RequeueAfter: r.ResyncPeriod}, nil
+}
+
+// isClusterSecret returns whether the given object is a cluster secret in an Argo instance namespace.
+func (r *ClusterSecretReconciler) isClusterSecret(o client.Object) bool {
+	return strings.HasPrefix(o.GetNamespace(), r.NamespacePrefix) &&
+		strings.HasSuffix(o.GetName(), "-"+common.SecretNameSuffix)
+}
+
+// clusterSecretOf returns a request for the cluster secret of the destination of the given Application, including
+// Applications that are being or were deleted.
+func (r *ClusterSecretReconciler) clusterSecretOf(ctx context.Context, o client.Object) []reconcile.Request {
+	app, ok := o.(*argoprojv1alpha1.Application)
+	if !ok || !strings.HasPrefix(app.Namespace, r.NamespacePrefix) {
+		return nil
+	}
+
+	destServer, err := utils.ResolveDestinationServer(ctx, r.Client, app)
+	if err != nil || utils.IsInCluster(destServer) {
+		return nil
+	}
+	secretName, err := utils.ClusterSecretName(destServer, r.ServerUrlDomain)
+	if err != nil {
+		secret, err := utils.FetchClusterSecret(ctx, r.Client, app.Namespace, destServer, r.ServerUrlDomain)
+		if err != nil {
+			return nil
+		}
+		secretName = secret.Name
+	}
+
+	return []reconcile.Request{{NamespacedName: client.ObjectKey{Namespace: app.Namespace, Name: secretName}}}
+}
+
+// SetupWithManager sets up the controller with the Manager. Only the metadata of Secrets is watched, so their data
+// is not cached.
+func (r *ClusterSecretReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("clustersecret").
+		For(&corev1.Secret{}, builder.OnlyMetadata, builder.WithPredicates(predicate.NewPredicateFuncs(r.isClusterSecret))).
+		Watches(&argoprojv1alpha1.Application{}, handler.EnqueueRequestsFromMapFunc(r.clusterSecretOf)).
+		Complete(r)
+}
@@ -0,0 +1,59 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	argoprojv1alpha1 "github.com/argoproj/argo-cd/v3/pkg/apis/application/v1alpha1"
+	"github.com/dana-team/application-rbac-validator/internal/common"
+	"github.com/dana-team/application-rbac-validator/internal/utils"
+	testutils "github.com/dana-team/application-rbac-validator/test/utils"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestClusterSecretReconcile(t *testing.T) {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-cluster.example.com-cluster-secret", Namespace: "argocd-team-a"},
+		Data:       map[string][]byte{common.NamespaceKey: []byte("team-a,deleted-team")},
+	}
+	app := testutils.GenerateTestApplication("argocd-team-a", "https://api.my-cluster.example.com:6443", "team-a")
+	reconciler := &ClusterSecretReconciler{
+		Client:          testutils.NewFakeClient(secret, app),
+		Recorder:        record.NewFakeRecorder(10),
+		NamespacePrefix: "argocd-",
+		ResyncPeriod:    DefaultClusterSecretResyncPeriod,
+	}
+
+	requests := reconciler.clusterSecretOf(context.Background(), app)
+	if len(requests) != 1 || requests[0].NamespacedName != client.ObjectKeyFromObject(secret) {
+		t.Fatalf("expected the Application to map to its cluster secret but got %v", requests)
+	}
+
+	result, err := reconciler.Reconcile(context.Background(), ctrl.Request{NamespacedName: client.ObjectKeyFromObject(secret)})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.RequeueAfter != DefaultClusterSecretResyncPeriod {
+		t.Errorf("expected requeue after %v but got %v", DefaultClusterSecretResyncPeriod, result.RequeueAfter)
+	}
+
+	updated := &corev1.Secret{}
+	if err := reconciler.Get(context.Background(), client.ObjectKeyFromObject(secret), updated); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if namespaces := utils.ExtractNamespacesFromSecret(updated); len(namespaces) != 1 || namespaces[0] != "team-a" {
+		t.Errorf("expected %v but got %v", []string{"team-a"}, namespaces)
+	}
+
+	outOfScope := testutils.GenerateTestApplication("other-namespace", "https://api.my-cluster.example.com:6443", "team-a")
+	if requests := reconciler.clusterSecretOf(context.Background(), outOfScope); len(requests) != 0 {
+		t.Errorf("expected Applications outside the namespace prefix to be ignored but got %v", requests)
+	}
+	if requests := reconciler.clusterSecretOf(context.Background(), &argoprojv1alpha1.Application{}); len(requests) != 0 {
+		t.Errorf("expected an Application without a destination to be ignored but got %v", requests)
+	}
+}
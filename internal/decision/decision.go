@@ -0,0 +1,214 @@
+package decision
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"text/template"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// Reason is a stable code identifying why an object was denied.
+type Reason string
+
+const (
+	// ReasonDestinationMissing is used when the destination lacks a namespace, server or name.
+	ReasonDestinationMissing Reason = "DestinationMissing"
+	// ReasonDestinationUnresolved is used when the destination cluster cannot be resolved to a server.
+	ReasonDestinationUnresolved Reason = "DestinationUnresolved"
+	// ReasonInClusterForbidden is used when the destination is the cluster the webhook runs in.
+	ReasonInClusterForbidden Reason = "InClusterForbidden"
+	// ReasonClusterNotAllowed is used when the ApplicationRBACPolicy does not allow the destination cluster.
+	ReasonClusterNotAllowed Reason = "ClusterNotAllowed"
+	// ReasonWildcardDestination is used when an AppProject grants a wildcard destination.
+	ReasonWildcardDestination Reason = "WildcardDestination"
+	// ReasonTokenMissing is used when there are no credentials for the destination cluster.
+	ReasonTokenMissing Reason = "TokenMissing"
+	// ReasonDestinationUnreachable is used when the destination cluster cannot be accessed.
+	ReasonDestinationUnreachable Reason = "DestinationUnreachable"
+	// ReasonDestinationUnauthorized is used when no admin has the required access to the destination namespace.
+	ReasonDestinationUnauthorized Reason = "DestinationUnauthorized"
+	// ReasonClusterScopeUnauthorized is used when no admin has the required cluster-scoped access.
+	ReasonClusterScopeUnauthorized Reason = "ClusterScopeUnauthorized"
+	// ReasonInvalidConfiguration is used when the configuration of the Argo instance or the webhook is invalid.
+	ReasonInvalidConfiguration Reason = "InvalidConfiguration"
+	// ReasonInternalError is used for errors that are not a Denial.
+	ReasonInternalError Reason = "InternalError"
+)
+
+// remediations are the remediation text templates of the reasons, rendered with the parameters of a Denial.
+var remediations = map[Reason]*template.Template{
+	ReasonDestinationMissing: newRemediation(
+		"Set the destination namespace and either the destination server or name."),
+	ReasonDestinationUnresolved: newRemediation(
+		"Reference the destination cluster by its server URL or by the name of an existing Argo CD cluster secret."),
+	ReasonInClusterForbidden: newRemediation(
+		"Deploy to a remote destination cluster instead of the cluster Argo CD runs in."),
+	ReasonClusterNotAllowed: newRemediation(
+		"Target one of the clusters allowed by ApplicationRBACPolicy {{.policy}}, or add {{.cluster}} to its allowedClusters."),
+	ReasonWildcardDestination: newRemediation(
+		"Replace the wildcard with concrete destinations, or create a BypassGrant in the namespace."),
+	ReasonTokenMissing: newRemediation(
+		"Store credentials for {{.server}} in a cluster credentials Secret or an Argo CD cluster secret."),
+	ReasonDestinationUnreachable: newRemediation(
+		"Check the credentials, CA bundle and network access of destination cluster {{.cluster}}."),
+	ReasonDestinationUnauthorized: newRemediation(
+		"Grant one of the Argo instance admins the access of permission profile {{.profile}} to namespace {{.namespace}} in cluster {{.cluster}}."),
+	ReasonClusterScopeUnauthorized: newRemediation(
+		"Grant one of the Argo instance admins the cluster-scoped access of permission profile {{.profile}} in cluster {{.cluster}}."),
+}
+
+// newRemediation parses a remediation text template, failing on parameters the Denial does not set.
+func newRemediation(text string) *template.Template {
+	return template.Must(template.New("remediation").Option("missingkey=error").Parse(text))
+}
+
+// Denial is a typed reason an object was denied, pointing at the offending field.
+type Denial struct {
+	// Reason is the stable code of the denial.
+	Reason Reason
+	// Field is the path of the offending field, e.g. "spec.destination.namespace".
+	Field string
+	// Params fill the remediation text template of the reason.
+	Params map[string]string
+
+	err error
+}
+
+// New returns a Denial with the given reason and field whose message is formatted from the given format.
+func New(reason Reason, field, format string, args ...any) *Denial {
+	return &Denial{Reason: reason, Field: field, err: fmt.Errorf(format, args...)}
+}
+
+// Wrap returns a Denial with the given reason and field for the given error, keeping its message.
+func Wrap(reason Reason, field string, err error) *Denial {
+	return &Denial{Reason: reason, Field: field, err: err}
+}
+
+// WithParams sets the parameters filling the remediation text template of the Denial.
+func (d *Denial) WithParams(params map[string]string) *Denial {
+	d.Params = params
+	return d
+}
+
+// Error implements error.
+func (d *Denial) Error() string {
+	return d.err.Error()
+}
+
+// Unwrap returns the error the Denial was created for.
+func (d *Denial) Unwrap() error {
+	return d.err
+}
+
+// Remediation returns the remediation text of the Denial, or an empty string if its reason has none or the Denial
+// lacks the parameters it requires.
+func (d *Denial) Remediation() string {
+	remediation, ok := remediations[d.Reason]
+	if !ok {
+		return ""
+	}
+
+	var text strings.Builder
+	if err := remediation.Execute(&text, d.Params); err != nil {
+		return ""
+	}
+
+	return text.String()
+}
+
+// Denials returns every Denial in the tree of the given error, in order.
+func Denials(err error) []*Denial {
+	if err == nil {
+		return nil
+	}
+
+	if denial, ok := err.(*Denial); ok {
+		return []*Denial{denial}
+	}
+
+	switch wrapped := err.(type) {
+	case interface{ Unwrap() []error }:
+		var denials []*Denial
+		for _, inner := range wrapped.Unwrap() {
+			denials = append(denials, Denials(inner)...)
+		}
+		return denials
+	case interface{ Unwrap() error }:
+		return Denials(wrapped.Unwrap())
+	}
+
+	return nil
+}
+
+// ReasonOf returns the reason of the first Denial in the tree of the given error, or ReasonInternalError if it holds
+// none.
+func ReasonOf(err error) Reason {
+	denials := Denials(err)
+	if len(denials) == 0 {
+		return ReasonInternalError
+	}
+
+	return denials[0].Reason
+}
+
+// WithFieldPrefix prefixes the field of every Denial in the tree of the given error with the given path, so that
+// checks can report fields relative to what they validate. Denials without a field concern the whole object and are
+// left as is. It returns the given error.
+func WithFieldPrefix(err error, prefix string) error {
+	for _, denial := range Denials(err) {
+		if denial.Field != "" {
+			denial.Field = prefix + "." + denial.Field
+		}
+	}
+
+	return err
+}
+
+// Causes returns a status cause for every Denial in the tree of the given error, or a single InternalError cause
+// if it holds none.
+func Causes(err error) []metav1.StatusCause {
+	denials := Denials(err)
+	if len(denials) == 0 {
+		return []metav1.StatusCause{{Type: metav1.CauseType(ReasonInternalError), Message: err.Error()}}
+	}
+
+	causes := make([]metav1.StatusCause, 0, len(denials))
+	for _, denial := range denials {
+		message := denial.Error()
+		if remediation := denial.Remediation(); remediation != "" {
+			message = fmt.Sprintf("%s. %s", message, remediation)
+		}
+		causes = append(causes, metav1.StatusCause{
+			Type:    metav1.CauseType(denial.Reason),
+			Message: message,
+			Field:   denial.Field,
+		})
+	}
+
+	return causes
+}
+
+// ToStatusError converts the given validation error of the given object into a Forbidden status error whose details
+// carry a cause for every Denial, keeping the message of the error. Status errors are returned as is.
+func ToStatusError(gk schema.GroupKind, name string, err error) error {
+	if _, ok := err.(*apierrors.StatusError); err == nil || ok {
+		return err
+	}
+
+	return &apierrors.StatusError{ErrStatus: metav1.Status{
+		Status:  metav1.StatusFailure,
+		Code:    http.StatusForbidden,
+		Reason:  metav1.StatusReasonForbidden,
+		Message: err.Error(),
+		Details: &metav1.StatusDetails{
+			Name:   name,
+			Group:  gk.Group,
+			Kind:   gk.Kind,
+			Causes: Causes(err),
+		},
+	}}
+}
@@ -0,0 +1,129 @@
+package decision
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestCauses(t *testing.T) {
+	testCases := []struct {
+		name     string
+		err      error
+		expected []metav1.StatusCause
+	}{
+		{
+			name: "should render the remediation of a denial",
+			err: New(ReasonClusterNotAllowed, "spec.destination.server", "cluster %s is not allowed", "team-b").
+				WithParams(map[string]string{"policy": "policy", "cluster": "team-b"}),
+			expected: []metav1.StatusCause{{
+				Type:    "ClusterNotAllowed",
+				Message: "cluster team-b is not allowed. Target one of the clusters allowed by ApplicationRBACPolicy policy, or add team-b to its allowedClusters.",
+				Field:   "spec.destination.server",
+			}},
+		},
+		{
+			name: "should omit the remediation of a denial lacking its parameters",
+			err:  New(ReasonTokenMissing, "spec.destination.server", "no credentials"),
+			expected: []metav1.StatusCause{{
+				Type:    "TokenMissing",
+				Message: "no credentials",
+				Field:   "spec.destination.server",
+			}},
+		},
+		{
+			name: "should return a cause for every joined denial",
+			err: fmt.Errorf("denied: %w", errors.Join(
+				New(ReasonDestinationMissing, "spec.destinations[0].namespace", "namespace missing"),
+				New(ReasonInvalidConfiguration, "", "invalid configuration"),
+			)),
+			expected: []metav1.StatusCause{
+				{
+					Type:    "DestinationMissing",
+					Message: "namespace missing. Set the destination namespace and either the destination server or name.",
+					Field:   "spec.destinations[0].namespace",
+				},
+				{Type: "InvalidConfiguration", Message: "invalid configuration"},
+			},
+		},
+		{
+			name:     "should return an internal error cause for an error without denials",
+			err:      errors.New("failed to list secrets"),
+			expected: []metav1.StatusCause{{Type: "InternalError", Message: "failed to list secrets"}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			causes := Causes(tc.err)
+			if len(causes) != len(tc.expected) {
+				t.Fatalf("expected %v but got %v", tc.expected, causes)
+			}
+			for i := range causes {
+				if causes[i] != tc.expected[i] {
+					t.Errorf("expected %v but got %v", tc.expected[i], causes[i])
+				}
+			}
+		})
+	}
+}
+
+func TestWithFieldPrefix(t *testing.T) {
+	denial := New(ReasonDestinationMissing, "namespace", "namespace missing")
+	objectDenial := New(ReasonInvalidConfiguration, "", "invalid configuration")
+	err := fmt.Errorf("destination: %w", errors.Join(denial, objectDenial))
+
+	WithFieldPrefix(WithFieldPrefix(err, "spec.destination"), "spec.template")
+
+	if denial.Field != "spec.template.spec.destination.namespace" {
+		t.Errorf("expected %v but got %v", "spec.template.spec.destination.namespace", denial.Field)
+	}
+	if objectDenial.Field != "" {
+		t.Errorf("expected an empty field but got %v", objectDenial.Field)
+	}
+}
+
+func TestToStatusError(t *testing.T) {
+	gk := schema.GroupKind{Group: "argoproj.io", Kind: "Application"}
+	err := fmt.Errorf("wrapped: %w", New(ReasonInClusterForbidden, "spec.destination.server", "in-cluster destination"))
+
+	statusErr := &apierrors.StatusError{}
+	if !errors.As(ToStatusError(gk, "app", err), &statusErr) {
+		t.Fatalf("expected a status error")
+	}
+
+	status := statusErr.Status()
+	if status.Code != http.StatusForbidden || status.Reason != metav1.StatusReasonForbidden {
+		t.Errorf("expected a Forbidden status but got %d/%s", status.Code, status.Reason)
+	}
+	if status.Message != err.Error() {
+		t.Errorf("expected %v but got %v", err.Error(), status.Message)
+	}
+	if status.Details == nil || status.Details.Name != "app" || status.Details.Kind != "Application" ||
+		len(status.Details.Causes) != 1 || status.Details.Causes[0].Type != "InClusterForbidden" {
+		t.Errorf("expected details of the Application with an InClusterForbidden cause but got %v", status.Details)
+	}
+
+	existing := apierrors.NewBadRequest("bad request")
+	if ToStatusError(gk, "app", existing) != error(existing) {
+		t.Errorf("expected a status error to be returned as is")
+	}
+}
+
+func TestReasonOf(t *testing.T) {
+	err := fmt.Errorf("denied: %w", errors.Join(
+		New(ReasonWildcardDestination, "namespace", "wildcard"),
+		New(ReasonClusterNotAllowed, "server", "not allowed"),
+	))
+	if reason := ReasonOf(err); reason != ReasonWildcardDestination {
+		t.Errorf("expected %v but got %v", ReasonWildcardDestination, reason)
+	}
+	if reason := ReasonOf(errors.New("failed to list secrets")); reason != ReasonInternalError {
+		t.Errorf("expected %v but got %v", ReasonInternalError, reason)
+	}
+}
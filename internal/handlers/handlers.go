@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"context"
+	"encoding/json"
 	"slices"
 
 	argoprojv1alpha1 "github.com/argoproj/argo-cd/v3/pkg/apis/application/v1alpha1"
@@ -11,13 +12,31 @@ import (
 	"github.com/go-logr/logr"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
-// HandleCreateOrUpdate handles the creation or update of an Application resource.
-func HandleCreateOrUpdate(log logr.Logger, ctx context.Context, cl client.Client, app *argoprojv1alpha1.Application) error {
+const (
+	// ReasonNamespaceAdded is the reason of the Event recorded when the destination namespace of an Application is
+	// added to the namespaces of its destination cluster secret.
+	ReasonNamespaceAdded = "NamespaceAdded"
+	// ReasonNamespaceRemoved is the reason of the Event recorded when the destination namespace of a deleted
+	// Application is removed from the namespaces of its destination cluster secret, or when a namespace no live
+	// Application targets is removed from a cluster secret.
+	ReasonNamespaceRemoved = "NamespaceRemoved"
+)
+
+// HandleCreateOrUpdate handles the creation or update of an Application resource. Short cluster names are resolved
+// with the given server URL domain.
+func HandleCreateOrUpdate(log logr.Logger, ctx context.Context, cl client.Client, recorder record.EventRecorder, app *argoprojv1alpha1.Application,
+	serverUrlDomain string) error {
 	destinationNS := app.Spec.Destination.Namespace
-	secret, err := utils.FetchDestinationClusterSecret(ctx, cl, app)
+	destinationKey, err := utils.ResolveDestinationKey(ctx, cl, app, serverUrlDomain)
+	if err != nil {
+		log.Error(err, "Failed to resolve destination server", "app", app.Name)
+		return err
+	}
+	secret, err := utils.FetchDestinationClusterSecret(ctx, cl, app, serverUrlDomain)
 	if err != nil {
 		log.Error(err, "Failed to fetch secret for application", "app", app.Name)
 		return err
@@ -25,22 +44,24 @@ func HandleCreateOrUpdate(log logr.Logger, ctx context.Context, cl client.Client
 
 	if utils.ShouldBypassOptimization(secret) {
 		log.Info("Bypass optimization label exists on destination secret, skipping ...", "app", app.Name, "cluster", app.Spec.Destination.Server)
-		metrics.ObserveApplicationOptimizationStatus(app.Name, app.Namespace, app.Spec.Destination.Namespace, app.Spec.Destination.Server, "bypass-label", false)
+		metrics.ObserveApplicationOptimizationStatus(app.Name, app.Namespace, app.Spec.Destination.Namespace, destinationKey, "bypass-label", false)
 		return nil
 	}
 
 	namespaceList := utils.ExtractNamespacesFromSecret(secret)
 	if destinationNS != "" && !slices.Contains(namespaceList, destinationNS) {
-		err = utils.RetryUpdateSecret(ctx, cl, app, append(namespaceList, destinationNS))
+		err = utils.RetryUpdateSecret(ctx, cl, app, append(namespaceList, destinationNS), serverUrlDomain)
 		if err != nil {
 			log.Error(err, "Failed to update secret", "secretName", secret.Name, "namespace", secret.Namespace, "destinationNS", destinationNS)
 			return err
 		}
 
 		log.Info("Updated secret with new namespace", "secretName", secret.Name, "namespace", destinationNS)
+		recorder.Eventf(app, corev1.EventTypeNormal, ReasonNamespaceAdded, "Added namespace %s to cluster secret %s/%s",
+			destinationNS, secret.Namespace, secret.Name)
 	}
 
-	metrics.ObserveApplicationOptimizationStatus(app.Name, app.Namespace, app.Spec.Destination.Namespace, app.Spec.Destination.Server, "optimized", true)
+	metrics.ObserveApplicationOptimizationStatus(app.Name, app.Namespace, app.Spec.Destination.Namespace, destinationKey, "optimized", true)
 
 	return nil
 
@@ -55,8 +76,11 @@ func IsClusterWide(secret *corev1.Secret) bool {
 	return clusterWide
 }
 
-// HandleDelete handles the deletion of an Application resource.
-func HandleDelete(log logr.Logger, ctx context.Context, cl client.Client, app *argoprojv1alpha1.Application) error {
+// HandleDelete handles the deletion of an Application resource, removing its destination namespace from the cluster
+// secret unless another Application still deploys to it or it is the last namespace of the secret, as an empty list
+// grants access to every namespace of the cluster. Short cluster names are resolved with the given server URL domain.
+func HandleDelete(log logr.Logger, ctx context.Context, cl client.Client, recorder record.EventRecorder, app *argoprojv1alpha1.Application,
+	serverUrlDomain string) error {
 	destServer, err := utils.ResolveDestinationServer(ctx, cl, app)
 	if err != nil {
 		log.Error(err, "Failed to resolve destination server", "app", app.Name)
@@ -65,14 +89,14 @@ func HandleDelete(log logr.Logger, ctx context.Context, cl client.Client, app *a
 
 	if utils.IsInCluster(destServer) {
 		log.Info("application is targeting in-cluster, ignoring...", "app", app.Name)
-		metrics.ObserveApplicationOptimizationStatus(app.Name, app.Namespace, app.Spec.Destination.Namespace, destServer, "in-cluster", false)
+		metrics.ObserveApplicationOptimizationStatus(app.Name, app.Namespace, app.Spec.Destination.Namespace, utils.DestinationKey(destServer, serverUrlDomain), "in-cluster", false)
 		return nil
 	}
-	secret, err := utils.FetchDestinationClusterSecret(ctx, cl, app)
+	secret, err := utils.FetchDestinationClusterSecret(ctx, cl, app, serverUrlDomain)
 	if err != nil {
 		if errors.IsNotFound(err) {
 			log.Info("secret not found, skipping namespace cleanup", "app", app.Name)
-			metrics.DeleteApplicationOptimizationStatus(app.Name, app.Namespace, app.Spec.Destination.Server)
+			metrics.DeleteApplicationOptimizationStatus(app.Name, app.Namespace)
 			return nil
 		}
 		log.Error(err, "Failed to fetch secret for application", "app", app.Name)
@@ -80,7 +104,7 @@ func HandleDelete(log logr.Logger, ctx context.Context, cl client.Client, app *a
 	}
 	if utils.ShouldBypassOptimization(secret) {
 		log.Info("Destination secret has bypass label, skipping...", "app", app.Name, "cluster", app.Spec.Destination.Server)
-		metrics.DeleteApplicationOptimizationStatus(app.Name, app.Namespace, app.Spec.Destination.Server)
+		metrics.DeleteApplicationOptimizationStatus(app.Name, app.Namespace)
 		return nil
 	}
 	destinationNS := app.Spec.Destination.Namespace
@@ -89,7 +113,12 @@ func HandleDelete(log logr.Logger, ctx context.Context, cl client.Client, app *a
 		log.Error(err, "Failed to list applications in namespace", "namespace", app.Namespace)
 		return err
 	}
-	if !utils.IsDestinationNamespaceInUse(applicationList, app, destinationNS) && destinationNS != "" {
+	inUse, err := utils.IsDestinationNamespaceInUse(ctx, cl, applicationList, app, destinationNS, serverUrlDomain)
+	if err != nil {
+		log.Error(err, "Failed to check whether the destination namespace is in use", "namespace", destinationNS)
+		return err
+	}
+	if !inUse && destinationNS != "" {
 		namespaceList := utils.ExtractNamespacesFromSecret(secret)
 		// Remove the namespace from the list
 		var newNamespaceList []string
@@ -98,16 +127,222 @@ func HandleDelete(log logr.Logger, ctx context.Context, cl client.Client, app *a
 				newNamespaceList = append(newNamespaceList, ns)
 			}
 		}
+		if len(newNamespaceList) == 0 {
+			log.Info("Destination namespace is the last namespace of the secret, keeping it", "secretName", secret.Name,
+				"namespace", destinationNS)
+			metrics.DeleteApplicationOptimizationStatus(app.Name, app.Namespace)
+			return nil
+		}
 
-		err = utils.RetryUpdateSecret(ctx, cl, app, newNamespaceList)
+		err = utils.RetryUpdateSecret(ctx, cl, app, newNamespaceList, serverUrlDomain)
 		if err != nil {
 			log.Error(err, "Failed to update secret", "secretName", secret.Name, "namespace", destinationNS)
 			return err
 		}
 
 		log.Info("Removed namespace from secret", "secretName", secret.Name, "namespace", destinationNS)
+		recorder.Eventf(app, corev1.EventTypeNormal, ReasonNamespaceRemoved, "Removed namespace %s from cluster secret %s/%s",
+			destinationNS, secret.Namespace, secret.Name)
 	}
-	metrics.DeleteApplicationOptimizationStatus(app.Name, app.Namespace, app.Spec.Destination.Server)
+	metrics.DeleteApplicationOptimizationStatus(app.Name, app.Namespace)
 	return nil
 
 }
+
+// SyncClusterSecretNamespaces recomputes the namespaces of the given cluster secret from the destination namespaces of
+// the live Applications of its namespace targeting its cluster, and updates the secret only when they changed. The
+// secret is treated as derived state, so namespaces no live Application targets anymore are removed, except that the
+// namespaces are never emptied, as Argo CD treats an empty list as access to every namespace of the cluster, and the
+// namespaces of live Applications whose destination cannot be resolved are kept. Short cluster names are resolved
+// with the given server URL domain.
+func SyncClusterSecretNamespaces(log logr.Logger, ctx context.Context, cl client.Client, recorder record.EventRecorder, secret *corev1.Secret,
+	serverUrlDomain string) error {
+	if utils.ShouldBypassOptimization(secret) {
+		log.Info("Bypass optimization label exists on cluster secret, skipping ...", "secretName", secret.Name)
+		return nil
+	}
+
+	applicationList := &argoprojv1alpha1.ApplicationList{}
+	if err := cl.List(ctx, applicationList, client.InNamespace(secret.Namespace)); err != nil {
+		log.Error(err, "Failed to list applications in namespace", "namespace", secret.Namespace)
+		return err
+	}
+
+	targeting := map[string][]*argoprojv1alpha1.Application{}
+	unresolved := map[string]bool{}
+	for i := range applicationList.Items {
+		app := &applicationList.Items[i]
+		destinationNS := app.Spec.Destination.Namespace
+		if !app.DeletionTimestamp.IsZero() || destinationNS == "" {
+			continue
+		}
+
+		destServer, err := utils.ResolveDestinationServer(ctx, cl, app)
+		if err != nil {
+			log.Error(err, "Failed to resolve destination server, skipping application", "app", app.Name)
+			unresolved[destinationNS] = true
+			continue
+		}
+		if !utils.IsClusterSecretOf(secret.Name, destServer, serverUrlDomain) {
+			continue
+		}
+		targeting[destinationNS] = append(targeting[destinationNS], app)
+	}
+
+	currentNamespaces := utils.ExtractNamespacesFromSecret(secret)
+	var namespaceList, added, removed []string
+	for destinationNS := range targeting {
+		namespaceList = append(namespaceList, destinationNS)
+		if !slices.Contains(currentNamespaces, destinationNS) {
+			added = append(added, destinationNS)
+		}
+	}
+	for _, ns := range currentNamespaces {
+		if _, ok := targeting[ns]; ok || ns == "" {
+			continue
+		}
+		if unresolved[ns] {
+			namespaceList = append(namespaceList, ns)
+			continue
+		}
+		removed = append(removed, ns)
+	}
+	if len(added) == 0 && len(removed) == 0 {
+		return nil
+	}
+	if len(namespaceList) == 0 {
+		log.Info("No live Application targets the cluster, leaving secret namespaces unchanged", "secretName", secret.Name,
+			"namespaces", currentNamespaces)
+		return nil
+	}
+	slices.Sort(namespaceList)
+
+	if err := utils.RetryUpdateSecretNamespaces(ctx, cl, client.ObjectKeyFromObject(secret), namespaceList); err != nil {
+		log.Error(err, "Failed to update secret", "secretName", secret.Name, "namespace", secret.Namespace)
+		return err
+	}
+
+	log.Info("Recomputed secret namespaces", "secretName", secret.Name, "added", added, "removed", removed)
+	for _, destinationNS := range added {
+		for _, app := range targeting[destinationNS] {
+			recorder.Eventf(app, corev1.EventTypeNormal, ReasonNamespaceAdded, "Added namespace %s to cluster secret %s/%s",
+				destinationNS, secret.Namespace, secret.Name)
+		}
+	}
+	for _, ns := range removed {
+		recorder.Eventf(secret, corev1.EventTypeNormal, ReasonNamespaceRemoved,
+			"Removed namespace %s from cluster secret %s/%s, as no live Application targets it", ns, secret.Namespace, secret.Name)
+	}
+
+	return nil
+}
+
+// appliedDestination is the destination of an Application whose namespace was added to a cluster secret, recorded in
+// the applied destination annotation of the Application.
+type appliedDestination struct {
+	Server    string `json:"server"`
+	Namespace string `json:"namespace"`
+}
+
+// RetractPreviousDestination removes the namespace of the previously applied destination of the Application from the
+// cluster secret of that destination once the Application targets another namespace or cluster, unless another
+// Application still deploys to that namespace in that cluster. It then records the given resolved destination server
+// and the destination namespace as the applied destination of the Application. Short cluster names are resolved with
+// the given server URL domain.
+func RetractPreviousDestination(log logr.Logger, ctx context.Context, cl client.Client, recorder record.EventRecorder,
+	app *argoprojv1alpha1.Application, destServer, serverUrlDomain string) error {
+	current := appliedDestination{Server: destServer, Namespace: app.Spec.Destination.Namespace}
+
+	var previous appliedDestination
+	raw, ok := app.Annotations[common.AppliedDestinationAnnotation]
+	if ok {
+		if err := json.Unmarshal([]byte(raw), &previous); err != nil {
+			log.Info("Ignoring malformed applied destination annotation", "app", app.Name, "annotation", raw)
+			ok = false
+		}
+	}
+	if ok && previous == current {
+		return nil
+	}
+
+	sameDestination := previous.Namespace == current.Namespace &&
+		utils.DestinationKey(previous.Server, serverUrlDomain) == utils.DestinationKey(current.Server, serverUrlDomain)
+	if ok && !sameDestination && previous.Namespace != "" && !utils.IsInCluster(previous.Server) {
+		if err := retractDestination(log, ctx, cl, recorder, app, previous, serverUrlDomain); err != nil {
+			return err
+		}
+	}
+
+	value, err := json.Marshal(current)
+	if err != nil {
+		return err
+	}
+	original := app.DeepCopy()
+	if app.Annotations == nil {
+		app.Annotations = map[string]string{}
+	}
+	app.Annotations[common.AppliedDestinationAnnotation] = string(value)
+	if err := cl.Patch(ctx, app, client.MergeFrom(original)); err != nil {
+		log.Error(err, "Failed to record the applied destination", "app", app.Name)
+		return err
+	}
+
+	return nil
+}
+
+// retractDestination removes the namespace of the given previous destination of the Application from the cluster
+// secret of that destination, unless another Application still deploys to that namespace in that cluster or it is the
+// last namespace of the secret, as an empty list grants access to every namespace of the cluster.
+func retractDestination(log logr.Logger, ctx context.Context, cl client.Client, recorder record.EventRecorder,
+	app *argoprojv1alpha1.Application, previous appliedDestination, serverUrlDomain string) error {
+	secret, err := utils.FetchClusterSecret(ctx, cl, app.Namespace, previous.Server, serverUrlDomain)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			log.Info("Previous destination secret not found, skipping namespace cleanup", "app", app.Name, "server", previous.Server)
+			return nil
+		}
+		log.Error(err, "Failed to fetch previous destination secret", "app", app.Name, "server", previous.Server)
+		return err
+	}
+	if utils.ShouldBypassOptimization(secret) {
+		log.Info("Previous destination secret has bypass label, skipping...", "app", app.Name, "server", previous.Server)
+		return nil
+	}
+
+	applicationList := &argoprojv1alpha1.ApplicationList{}
+	if err := cl.List(ctx, applicationList, client.InNamespace(app.Namespace)); err != nil {
+		log.Error(err, "Failed to list applications in namespace", "namespace", app.Namespace)
+		return err
+	}
+	previousApp := app.DeepCopy()
+	previousApp.Spec.Destination = argoprojv1alpha1.ApplicationDestination{Server: previous.Server, Namespace: previous.Namespace}
+	inUse, err := utils.IsDestinationNamespaceInUse(ctx, cl, applicationList, previousApp, previous.Namespace, serverUrlDomain)
+	if err != nil {
+		log.Error(err, "Failed to check whether the previous destination namespace is in use", "namespace", previous.Namespace)
+		return err
+	}
+	if inUse {
+		return nil
+	}
+
+	namespaceList := utils.ExtractNamespacesFromSecret(secret)
+	if !slices.Contains(namespaceList, previous.Namespace) {
+		return nil
+	}
+	namespaceList = slices.DeleteFunc(namespaceList, func(ns string) bool { return ns == previous.Namespace })
+	if len(namespaceList) == 0 {
+		log.Info("Previous destination namespace is the last namespace of the secret, keeping it", "secretName", secret.Name,
+			"namespace", previous.Namespace)
+		return nil
+	}
+	if err := utils.RetryUpdateSecretNamespaces(ctx, cl, client.ObjectKeyFromObject(secret), namespaceList); err != nil {
+		log.Error(err, "Failed to update secret", "secretName", secret.Name, "namespace", previous.Namespace)
+		return err
+	}
+
+	log.Info("Removed previous destination namespace from secret", "secretName", secret.Name, "namespace", previous.Namespace)
+	recorder.Eventf(app, corev1.EventTypeNormal, ReasonNamespaceRemoved,
+		"Removed previous destination namespace %s from cluster secret %s/%s", previous.Namespace, secret.Namespace, secret.Name)
+
+	return nil
+}
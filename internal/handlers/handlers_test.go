@@ -2,7 +2,9 @@ package handlers
 
 import (
 	"context"
+	"strings"
 	"testing"
+	"time"
 
 	argoprojv1alpha1 "github.com/argoproj/argo-cd/v3/pkg/apis/application/v1alpha1"
 	"github.com/dana-team/application-rbac-validator/internal/common"
@@ -13,16 +15,18 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 const (
-	testNamespace      = "test-namespace"
-	testAppName        = "test-app"
-	testDestNamespace  = "dest-namespace"
-	testDestNamespace2 = "dest-namespace-2"
-	testClusterServer  = "https://api.test-cluster.example.com:6443"
-	testSecretName     = "test-cluster.example.com-cluster-secret"
+	testNamespace       = "test-namespace"
+	testAppName         = "test-app"
+	testDestNamespace   = "dest-namespace"
+	testDestNamespace2  = "dest-namespace-2"
+	testClusterServer   = "https://api.test-cluster.example.com:6443"
+	testSecretName      = "test-cluster.example.com-cluster-secret"
+	testServerUrlDomain = "example.com"
 )
 
 func TestHandleCreateOrUpdate(t *testing.T) {
@@ -32,6 +36,7 @@ func TestHandleCreateOrUpdate(t *testing.T) {
 		secret         *corev1.Secret
 		expectError    bool
 		expectUpdate   bool
+		expectEvent    bool
 		expectedNsList []string
 	}{
 		{
@@ -59,6 +64,7 @@ func TestHandleCreateOrUpdate(t *testing.T) {
 			},
 			expectError:    false,
 			expectUpdate:   true,
+			expectEvent:    true,
 			expectedNsList: []string{testDestNamespace},
 		},
 		{
@@ -113,6 +119,7 @@ func TestHandleCreateOrUpdate(t *testing.T) {
 			},
 			expectError:    false,
 			expectUpdate:   true,
+			expectEvent:    true,
 			expectedNsList: []string{testDestNamespace, testDestNamespace2},
 		},
 		{
@@ -141,6 +148,7 @@ func TestHandleCreateOrUpdate(t *testing.T) {
 			},
 			expectError:  false,
 			expectUpdate: false,
+			expectEvent:  true,
 		},
 	}
 
@@ -156,7 +164,9 @@ func TestHandleCreateOrUpdate(t *testing.T) {
 			log := logr.Discard()
 			ctx := context.Background()
 
-			err := HandleCreateOrUpdate(log, ctx, cl, tc.app)
+			recorder := record.NewFakeRecorder(10)
+
+			err := HandleCreateOrUpdate(log, ctx, cl, recorder, tc.app, testServerUrlDomain)
 
 			if tc.expectError && err == nil {
 				t.Errorf("expected error but got none")
@@ -205,6 +215,9 @@ func TestHandleCreateOrUpdate(t *testing.T) {
 				t.Fatalf("failed to get application: %v", err)
 			}
 
+			if events := len(recorder.Events); events != map[bool]int{true: 1, false: 0}[tc.expectEvent] {
+				t.Errorf("expected an Event only when the namespace is added but got %d Events", events)
+			}
 		})
 	}
 }
@@ -219,7 +232,7 @@ func TestHandleDelete(t *testing.T) {
 		expectedNsList []string
 	}{
 		{
-			name: "should remove namespace when no other app uses it",
+			name: "should keep namespace when it is the last namespace of the secret",
 			app: &argoprojv1alpha1.Application{
 				ObjectMeta: metav1.ObjectMeta{
 					Name:      testAppName,
@@ -243,7 +256,7 @@ func TestHandleDelete(t *testing.T) {
 				},
 			},
 			expectError:    false,
-			expectedNsList: []string{},
+			expectedNsList: []string{testDestNamespace},
 		},
 		{
 			name: "should keep namespace when other app uses it",
@@ -312,6 +325,46 @@ func TestHandleDelete(t *testing.T) {
 			expectError:    false,
 			expectedNsList: []string{testDestNamespace2},
 		},
+		{
+			name: "should keep namespace when another app using it cannot be resolved",
+			app: &argoprojv1alpha1.Application{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      testAppName,
+					Namespace: testNamespace,
+				},
+				Spec: argoprojv1alpha1.ApplicationSpec{
+					Destination: argoprojv1alpha1.ApplicationDestination{
+						Server:    testClusterServer,
+						Namespace: testDestNamespace,
+					},
+				},
+			},
+			otherApps: []*argoprojv1alpha1.Application{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "other-app",
+						Namespace: testNamespace,
+					},
+					Spec: argoprojv1alpha1.ApplicationSpec{
+						Destination: argoprojv1alpha1.ApplicationDestination{
+							Name:      "missing-cluster",
+							Namespace: testDestNamespace,
+						},
+					},
+				},
+			},
+			secret: &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      testSecretName,
+					Namespace: testNamespace,
+				},
+				Data: map[string][]byte{
+					common.NamespaceKey: []byte(testDestNamespace + "," + testDestNamespace2),
+				},
+			},
+			expectError:    false,
+			expectedNsList: []string{testDestNamespace, testDestNamespace2},
+		},
 	}
 
 	for _, tc := range testCases {
@@ -333,7 +386,7 @@ func TestHandleDelete(t *testing.T) {
 			log := logr.Discard()
 			ctx := context.Background()
 
-			err := HandleDelete(log, ctx, cl, tc.app)
+			err := HandleDelete(log, ctx, cl, record.NewFakeRecorder(10), tc.app, testServerUrlDomain)
 
 			if tc.expectError && err == nil {
 				t.Errorf("expected error but got none")
@@ -363,3 +416,243 @@ func TestHandleDelete(t *testing.T) {
 		})
 	}
 }
+
+func TestSyncClusterSecretNamespaces(t *testing.T) {
+	newApp := func(name, destinationNS string, deleting bool) *argoprojv1alpha1.Application {
+		app := &argoprojv1alpha1.Application{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: testNamespace,
+			},
+			Spec: argoprojv1alpha1.ApplicationSpec{
+				Destination: argoprojv1alpha1.ApplicationDestination{
+					Server:    testClusterServer,
+					Namespace: destinationNS,
+				},
+			},
+		}
+		if deleting {
+			app.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+			app.Finalizers = []string{"resources-finalizer.argocd.argoproj.io"}
+		}
+		return app
+	}
+	unresolvedApp := func(name, destinationNS string) *argoprojv1alpha1.Application {
+		app := newApp(name, destinationNS, false)
+		app.Spec.Destination = argoprojv1alpha1.ApplicationDestination{Name: "missing-cluster", Namespace: destinationNS}
+		return app
+	}
+
+	testCases := []struct {
+		name             string
+		apps             []*argoprojv1alpha1.Application
+		secretNamespaces string
+		secretLabels     map[string]string
+		expectedNsList   []string
+		expectedEvents   int
+	}{
+		{
+			name:             "should add the namespaces of the live applications",
+			apps:             []*argoprojv1alpha1.Application{newApp("app-1", testDestNamespace2, false), newApp("app-2", testDestNamespace, false)},
+			secretNamespaces: "",
+			expectedNsList:   []string{testDestNamespace, testDestNamespace2},
+			expectedEvents:   2,
+		},
+		{
+			name:             "should remove the namespaces no live application targets",
+			apps:             []*argoprojv1alpha1.Application{newApp("app-1", testDestNamespace, false), newApp("app-2", testDestNamespace2, true)},
+			secretNamespaces: testDestNamespace + "," + testDestNamespace2 + ",stale-namespace",
+			expectedNsList:   []string{testDestNamespace},
+			expectedEvents:   2,
+		},
+		{
+			name:             "should not update the secret when the namespaces did not change",
+			apps:             []*argoprojv1alpha1.Application{newApp("app-1", testDestNamespace, false), newApp("app-2", testDestNamespace2, false)},
+			secretNamespaces: testDestNamespace2 + "," + testDestNamespace,
+			expectedNsList:   []string{testDestNamespace2, testDestNamespace},
+		},
+		{
+			name:             "should skip the applications whose destination cannot be resolved",
+			apps:             []*argoprojv1alpha1.Application{newApp("app-1", testDestNamespace, false), unresolvedApp("app-2", testDestNamespace2)},
+			secretNamespaces: "",
+			expectedNsList:   []string{testDestNamespace},
+			expectedEvents:   1,
+		},
+		{
+			name:             "should keep the namespaces of the applications whose destination cannot be resolved",
+			apps:             []*argoprojv1alpha1.Application{newApp("app-1", testDestNamespace, false), unresolvedApp("app-2", testDestNamespace2)},
+			secretNamespaces: testDestNamespace2 + ",stale-namespace",
+			expectedNsList:   []string{testDestNamespace, testDestNamespace2},
+			expectedEvents:   2,
+		},
+		{
+			name:             "should not empty the namespaces when no live application targets the cluster",
+			apps:             []*argoprojv1alpha1.Application{newApp("app-1", testDestNamespace, true)},
+			secretNamespaces: testDestNamespace,
+			expectedNsList:   []string{testDestNamespace},
+		},
+		{
+			name:             "should skip secrets with the bypass optimization label",
+			apps:             []*argoprojv1alpha1.Application{newApp("app-1", testDestNamespace, false)},
+			secretNamespaces: "stale-namespace",
+			secretLabels:     map[string]string{common.BypassOptimizationLabel: common.LabelValueTrue},
+			expectedNsList:   []string{"stale-namespace"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			secret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      testSecretName,
+					Namespace: testNamespace,
+					Labels:    tc.secretLabels,
+				},
+				Data: map[string][]byte{
+					common.NamespaceKey: []byte(tc.secretNamespaces),
+				},
+			}
+			objects := []client.Object{secret}
+			for _, app := range tc.apps {
+				objects = append(objects, app)
+			}
+			cl := testutils.NewFakeClient(objects...)
+			ctx := context.Background()
+			recorder := record.NewFakeRecorder(10)
+
+			if err := SyncClusterSecretNamespaces(logr.Discard(), ctx, cl, recorder, secret, testServerUrlDomain); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			updatedSecret := &corev1.Secret{}
+			if err := cl.Get(ctx, client.ObjectKeyFromObject(secret), updatedSecret); err != nil {
+				t.Fatalf("failed to get secret: %v", err)
+			}
+			if actual := utils.ExtractNamespacesFromSecret(updatedSecret); strings.Join(actual, ",") != strings.Join(tc.expectedNsList, ",") {
+				t.Errorf("expected namespace list %v but got %v", tc.expectedNsList, actual)
+			}
+			if events := len(recorder.Events); events != tc.expectedEvents {
+				t.Errorf("expected %d Events but got %d", tc.expectedEvents, events)
+			}
+		})
+	}
+}
+
+func TestRetractPreviousDestination(t *testing.T) {
+	newApp := func(name, destinationNS, appliedNS string) *argoprojv1alpha1.Application {
+		app := &argoprojv1alpha1.Application{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: testNamespace,
+			},
+			Spec: argoprojv1alpha1.ApplicationSpec{
+				Destination: argoprojv1alpha1.ApplicationDestination{
+					Server:    testClusterServer,
+					Namespace: destinationNS,
+				},
+			},
+		}
+		if appliedNS != "" {
+			app.Annotations = map[string]string{
+				common.AppliedDestinationAnnotation: `{"server":"` + testClusterServer + `","namespace":"` + appliedNS + `"}`,
+			}
+		}
+		return app
+	}
+
+	testCases := []struct {
+		name             string
+		app              *argoprojv1alpha1.Application
+		otherApps        []*argoprojv1alpha1.Application
+		secretNamespaces string
+		secretLabels     map[string]string
+		expectedNsList   []string
+		expectedEvents   int
+	}{
+		{
+			name:             "should only record the applied destination of a new application",
+			app:              newApp(testAppName, testDestNamespace, ""),
+			secretNamespaces: testDestNamespace,
+			expectedNsList:   []string{testDestNamespace},
+		},
+		{
+			name:             "should not change the secret when the destination did not change",
+			app:              newApp(testAppName, testDestNamespace, testDestNamespace),
+			secretNamespaces: testDestNamespace,
+			expectedNsList:   []string{testDestNamespace},
+		},
+		{
+			name:             "should remove the previous namespace when no other application uses it",
+			app:              newApp(testAppName, testDestNamespace2, testDestNamespace),
+			secretNamespaces: testDestNamespace + "," + testDestNamespace2,
+			expectedNsList:   []string{testDestNamespace2},
+			expectedEvents:   1,
+		},
+		{
+			name:             "should keep the previous namespace when another application uses it",
+			app:              newApp(testAppName, testDestNamespace2, testDestNamespace),
+			otherApps:        []*argoprojv1alpha1.Application{newApp("other-app", testDestNamespace, "")},
+			secretNamespaces: testDestNamespace + "," + testDestNamespace2,
+			expectedNsList:   []string{testDestNamespace, testDestNamespace2},
+		},
+		{
+			name:             "should keep the previous namespace when it is the last namespace of the secret",
+			app:              newApp(testAppName, testDestNamespace2, testDestNamespace),
+			secretNamespaces: testDestNamespace,
+			expectedNsList:   []string{testDestNamespace},
+		},
+		{
+			name:             "should skip secrets with the bypass optimization label",
+			app:              newApp(testAppName, testDestNamespace2, testDestNamespace),
+			secretNamespaces: testDestNamespace + "," + testDestNamespace2,
+			secretLabels:     map[string]string{common.BypassOptimizationLabel: common.LabelValueTrue},
+			expectedNsList:   []string{testDestNamespace, testDestNamespace2},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			secret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      testSecretName,
+					Namespace: testNamespace,
+					Labels:    tc.secretLabels,
+				},
+				Data: map[string][]byte{
+					common.NamespaceKey: []byte(tc.secretNamespaces),
+				},
+			}
+			objects := []client.Object{secret, tc.app}
+			for _, app := range tc.otherApps {
+				objects = append(objects, app)
+			}
+			cl := testutils.NewFakeClient(objects...)
+			ctx := context.Background()
+			recorder := record.NewFakeRecorder(10)
+
+			if err := RetractPreviousDestination(logr.Discard(), ctx, cl, recorder, tc.app, testClusterServer, testServerUrlDomain); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			updatedSecret := &corev1.Secret{}
+			if err := cl.Get(ctx, client.ObjectKeyFromObject(secret), updatedSecret); err != nil {
+				t.Fatalf("failed to get secret: %v", err)
+			}
+			if actual := utils.ExtractNamespacesFromSecret(updatedSecret); strings.Join(actual, ",") != strings.Join(tc.expectedNsList, ",") {
+				t.Errorf("expected namespace list %v but got %v", tc.expectedNsList, actual)
+			}
+			if events := len(recorder.Events); events != tc.expectedEvents {
+				t.Errorf("expected %d Events but got %d", tc.expectedEvents, events)
+			}
+
+			updatedApp := &argoprojv1alpha1.Application{}
+			if err := cl.Get(ctx, client.ObjectKeyFromObject(tc.app), updatedApp); err != nil {
+				t.Fatalf("failed to get application: %v", err)
+			}
+			expected := `{"server":"` + testClusterServer + `","namespace":"` + tc.app.Spec.Destination.Namespace + `"}`
+			if actual := updatedApp.Annotations[common.AppliedDestinationAnnotation]; actual != expected {
+				t.Errorf("expected applied destination %v but got %v", expected, actual)
+			}
+		})
+	}
+}
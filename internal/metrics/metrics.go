@@ -1,6 +1,8 @@
 package metrics
 
 import (
+	"time"
+
 	"github.com/prometheus/client_golang/prometheus"
 	"sigs.k8s.io/controller-runtime/pkg/metrics"
 )
@@ -8,9 +10,54 @@ import (
 func InitializeMetrics() {
 	metrics.Registry.MustRegister(
 		applicationOptimizationStatus,
+		validationWarnings,
+		destinationTokenValid,
+		destinationTokenExpiry,
+		destinationTokenIssued,
+		bypassGrantExpiry,
+		bypassGrantExpiringSoon,
+		admissionDecisions,
+		validationStageDuration,
+		applicationCompliance,
 	)
 }
 
+const (
+	// OutcomeAllowed is the outcome of an admitted object without violations.
+	OutcomeAllowed = "allowed"
+	// OutcomeWarned is the outcome of an object admitted despite a violation because of the warn enforcement mode.
+	OutcomeWarned = "warned"
+	// OutcomeDenied is the outcome of a denied object.
+	OutcomeDenied = "denied"
+)
+
+const (
+	// ReasonNotSpecUpdate is the reason of an update admitted without validation because it changes no spec field.
+	ReasonNotSpecUpdate = "NotSpecUpdate"
+	// ReasonAutoSyncDisabled is the reason of an update admitted without validation because it only disables the
+	// automated sync of an Application.
+	ReasonAutoSyncDisabled = "AutoSyncDisabled"
+	// ReasonDestinationsUnchanged is the reason of an AppProject update admitted without validation because it
+	// changes no destination.
+	ReasonDestinationsUnchanged = "DestinationsUnchanged"
+)
+
+const (
+	// StageServerResolution is the validation stage resolving the destination server.
+	StageServerResolution = "server_resolution"
+	// StageBypassLookup is the validation stage looking up bypass labels and grants.
+	StageBypassLookup = "bypass_lookup"
+	// StageConfigLookup is the validation stage fetching the argo instance configuration and the
+	// ApplicationRBACPolicy.
+	StageConfigLookup = "config_lookup"
+	// StageTokenFetch is the validation stage fetching the destination cluster credentials.
+	StageTokenFetch = "token_fetch"
+	// StageClientBuild is the validation stage building the destination cluster client.
+	StageClientBuild = "client_build"
+	// StageAccessReview is the validation stage reviewing the access of the admins on the destination cluster.
+	StageAccessReview = "access_review"
+)
+
 var (
 	applicationOptimizationStatus = prometheus.NewGaugeVec(
 		prometheus.GaugeOpts{
@@ -19,15 +66,163 @@ var (
 		},
 		[]string{"name", "application_namespace", "destination_namespace", "destination", "reason"},
 	)
+
+	validationWarnings = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "application_rbac_validation_warnings_total",
+			Help: "Number of objects admitted with a violation because their enforcement mode is warn",
+		},
+		[]string{"kind", "cluster"},
+	)
+
+	destinationTokenValid = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "application_rbac_destination_token_valid",
+			Help: "Indicates whether the token of the destination cluster passed its last probe (1) or not (0)",
+		},
+		[]string{"cluster"},
+	)
+
+	destinationTokenExpiry = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "application_rbac_destination_token_expiry_timestamp_seconds",
+			Help: "Expiry of the token of the destination cluster as a Unix timestamp, for tokens with an exp claim",
+		},
+		[]string{"cluster"},
+	)
+
+	destinationTokenIssued = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "application_rbac_destination_token_issued_timestamp_seconds",
+			Help: "Issue time of the token of the destination cluster as a Unix timestamp, for tokens with an iat claim",
+		},
+		[]string{"cluster"},
+	)
+
+	bypassGrantExpiry = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "application_rbac_bypass_grant_expiry_timestamp_seconds",
+			Help: "Expiry of the bypass grant as a Unix timestamp",
+		},
+		[]string{"namespace", "name", "cluster"},
+	)
+
+	bypassGrantExpiringSoon = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "application_rbac_bypass_grant_expiring_soon",
+			Help: "Indicates whether the bypass grant expires within the warning threshold (1) or not (0)",
+		},
+		[]string{"namespace", "name", "cluster"},
+	)
+
+	admissionDecisions = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "application_rbac_admission_decisions_total",
+			Help: "Number of admission decisions by outcome and, for violations, reason code",
+		},
+		[]string{"kind", "outcome", "reason", "cluster", "instance"},
+	)
+
+	validationStageDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "application_rbac_validation_stage_duration_seconds",
+			Help:    "Duration of each validation stage in seconds",
+			Buckets: prometheus.DefBuckets,
+		},
+		[]string{"stage"},
+	)
+
+	applicationCompliance = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "application_rbac_application_compliant",
+			Help: "Indicates whether the Application passed its last re-validation (1) or not (0), with the reason code of its violation",
+		},
+		[]string{"namespace", "name", "cluster", "reason"},
+	)
 )
 
-// ObserveApplicationOptimizationStatus sets the optimization status metric for a given application.
+// ObserveApplicationOptimizationStatus sets the optimization status metric for a given application, replacing the one
+// of its previous destination. The destination is the destination key of its destination cluster.
 func ObserveApplicationOptimizationStatus(name, appNamespace, destinationNamespace, destination, reason string, optimized bool) {
 	value := map[bool]float64{true: 1, false: 0}[optimized]
+	DeleteApplicationOptimizationStatus(name, appNamespace)
 	applicationOptimizationStatus.WithLabelValues(name, appNamespace, destinationNamespace, destination, reason).Set(value)
 }
 
-// DeleteApplicationOptimizationStatus deletes the optimization metric for the given application.
-func DeleteApplicationOptimizationStatus(name, appNamespace, destination string) {
-	applicationOptimizationStatus.DeleteLabelValues(name, appNamespace, destination)
+// DeleteApplicationOptimizationStatus deletes the optimization metric for the given application, whatever its
+// destination and reason.
+func DeleteApplicationOptimizationStatus(name, appNamespace string) {
+	applicationOptimizationStatus.DeletePartialMatch(prometheus.Labels{"name": name, "application_namespace": appNamespace})
+}
+
+// IncValidationWarnings counts an object that was admitted despite a violation because of the warn enforcement mode.
+func IncValidationWarnings(kind, cluster string) {
+	validationWarnings.WithLabelValues(kind, cluster).Inc()
+}
+
+// ObserveDestinationTokenValidity sets whether the token of the given destination cluster passed its last probe.
+func ObserveDestinationTokenValidity(cluster string, valid bool) {
+	value := map[bool]float64{true: 1, false: 0}[valid]
+	destinationTokenValid.WithLabelValues(cluster).Set(value)
+}
+
+// ObserveDestinationTokenLifetime sets the expiry and issue time of the token of the given destination cluster.
+// A zero time deletes the matching metric, as the token does not carry the claim.
+func ObserveDestinationTokenLifetime(cluster string, expiresAt, issuedAt time.Time) {
+	observeTimestamp(destinationTokenExpiry, cluster, expiresAt)
+	observeTimestamp(destinationTokenIssued, cluster, issuedAt)
+}
+
+// DeleteDestinationTokenMetrics deletes the token metrics of the given destination cluster.
+func DeleteDestinationTokenMetrics(cluster string) {
+	destinationTokenValid.DeleteLabelValues(cluster)
+	destinationTokenExpiry.DeleteLabelValues(cluster)
+	destinationTokenIssued.DeleteLabelValues(cluster)
+}
+
+// observeTimestamp sets the given gauge to the given time, or deletes it when the time is zero.
+func observeTimestamp(gauge *prometheus.GaugeVec, cluster string, timestamp time.Time) {
+	if timestamp.IsZero() {
+		gauge.DeleteLabelValues(cluster)
+		return
+	}
+	gauge.WithLabelValues(cluster).Set(float64(timestamp.Unix()))
+}
+
+// ObserveBypassGrant sets the expiry of the given bypass grant and whether it expires within the warning threshold.
+func ObserveBypassGrant(namespace, name, cluster string, expiresAt time.Time, expiringSoon bool) {
+	value := map[bool]float64{true: 1, false: 0}[expiringSoon]
+	bypassGrantExpiry.WithLabelValues(namespace, name, cluster).Set(float64(expiresAt.Unix()))
+	bypassGrantExpiringSoon.WithLabelValues(namespace, name, cluster).Set(value)
+}
+
+// DeleteBypassGrantMetrics deletes the metrics of the given bypass grant, whatever its cluster.
+func DeleteBypassGrantMetrics(namespace, name string) {
+	labels := prometheus.Labels{"namespace": namespace, "name": name}
+	bypassGrantExpiry.DeletePartialMatch(labels)
+	bypassGrantExpiringSoon.DeletePartialMatch(labels)
+}
+
+// IncAdmissionDecisions counts an admission decision on an object of the given kind. The reason is the reason code
+// of the violation, and is empty for allowed objects.
+func IncAdmissionDecisions(kind, outcome, reason, cluster, instance string) {
+	admissionDecisions.WithLabelValues(kind, outcome, reason, cluster, instance).Inc()
+}
+
+// ObserveValidationStage observes the duration of the given validation stage, which started at the given time.
+func ObserveValidationStage(stage string, start time.Time) {
+	validationStageDuration.WithLabelValues(stage).Observe(time.Since(start).Seconds())
+}
+
+// ObserveApplicationCompliance sets whether the given Application passed its last re-validation. The reason is the
+// reason code of its violation, and is empty for compliant Applications.
+func ObserveApplicationCompliance(namespace, name, cluster, reason string, compliant bool) {
+	value := map[bool]float64{true: 1, false: 0}[compliant]
+	DeleteApplicationCompliance(namespace, name)
+	applicationCompliance.WithLabelValues(namespace, name, cluster, reason).Set(value)
+}
+
+// DeleteApplicationCompliance deletes the compliance metric of the given Application, whatever its cluster and reason.
+func DeleteApplicationCompliance(namespace, name string) {
+	applicationCompliance.DeletePartialMatch(prometheus.Labels{"namespace": namespace, "name": name})
 }
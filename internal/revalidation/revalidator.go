@@ -0,0 +1,220 @@
+package revalidation
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	argoprojv1alpha1 "github.com/argoproj/argo-cd/v3/pkg/apis/application/v1alpha1"
+	"github.com/dana-team/application-rbac-validator/internal/common"
+	"github.com/dana-team/application-rbac-validator/internal/decision"
+	"github.com/dana-team/application-rbac-validator/internal/metrics"
+	"github.com/dana-team/application-rbac-validator/internal/utils"
+	webhookargoprojv1alpha1 "github.com/dana-team/application-rbac-validator/internal/webhook/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+)
+
+// DefaultInterval is the default interval between two re-validations of the existing Applications.
+const DefaultInterval = time.Hour
+
+// Event reasons emitted on re-validated Applications.
+const (
+	ReasonRevalidationFailed    = "RevalidationFailed"
+	ReasonRevalidationSucceeded = "RevalidationSucceeded"
+	ReasonAutoSyncDisabled      = "AutoSyncDisabled"
+)
+
+// conclusiveReasons are the reasons of the violations a re-validation reports. The other reasons, such as an
+// unreachable destination cluster or a missing token, may be transient and are not held against the Application.
+var conclusiveReasons = map[decision.Reason]bool{
+	decision.ReasonDestinationMissing:       true,
+	decision.ReasonInClusterForbidden:       true,
+	decision.ReasonClusterNotAllowed:        true,
+	decision.ReasonWildcardDestination:      true,
+	decision.ReasonDestinationUnauthorized:  true,
+	decision.ReasonClusterScopeUnauthorized: true,
+}
+
+// Revalidator periodically re-runs the validation of the existing Applications, so an Application whose destination
+// was authorized when it was admitted but no longer is, e.g. because a RoleBinding of the admins was removed, does
+// not keep deploying unnoticed. Each Application is annotated with the result of its last re-validation, and the
+// result is reported as a metric and as Events on the Application. Optionally, the automated sync of non-compliant
+// Applications whose enforcement mode is enforce is disabled.
+type Revalidator struct {
+	Client          client.Client
+	Recorder        record.EventRecorder
+	ValidatorConfig webhookargoprojv1alpha1.ValidatorConfig
+	NamespacePrefix string
+	Interval        time.Duration
+	DisableAutoSync bool
+
+	observed map[types.NamespacedName]bool
+	validate func(ctx context.Context, application *argoprojv1alpha1.Application) error
+}
+
+// NewRevalidator returns a Revalidator re-validating the Applications in the namespaces with the given prefix every
+// interval.
+func NewRevalidator(k8sClient client.Client, recorder record.EventRecorder, config webhookargoprojv1alpha1.ValidatorConfig,
+	namespacePrefix string, interval time.Duration, disableAutoSync bool) *Revalidator {
+	r := &Revalidator{
+		Client:          k8sClient,
+		Recorder:        recorder,
+		ValidatorConfig: config,
+		NamespacePrefix: namespacePrefix,
+		Interval:        interval,
+		DisableAutoSync: disableAutoSync,
+		observed:        map[types.NamespacedName]bool{},
+	}
+	r.validate = func(ctx context.Context, application *argoprojv1alpha1.Application) error {
+		return webhookargoprojv1alpha1.ValidateApplication(ctx, r.Client, application, r.ValidatorConfig)
+	}
+
+	return r
+}
+
+// Start re-validates the Applications right away and then every interval until the context is done. It implements
+// manager.Runnable.
+func (r *Revalidator) Start(ctx context.Context) error {
+	logger := zap.New().WithName("revalidation")
+
+	ticker := time.NewTicker(r.Interval)
+	defer ticker.Stop()
+
+	for {
+		if err := r.RevalidateAll(ctx); err != nil {
+			logger.Error(err, "Failed to re-validate Applications")
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable, so a single replica re-validates the Applications
+// and emits Events.
+func (r *Revalidator) NeedLeaderElection() bool {
+	return true
+}
+
+// RevalidateAll re-validates every Application in the namespaces with the prefix that is not being deleted, and
+// deletes the metrics of the Applications that no longer exist.
+func (r *Revalidator) RevalidateAll(ctx context.Context) error {
+	applicationList := &argoprojv1alpha1.ApplicationList{}
+	if err := r.Client.List(ctx, applicationList); err != nil {
+		return fmt.Errorf("failed to list Applications: %w", err)
+	}
+
+	observed := map[types.NamespacedName]bool{}
+	for i := range applicationList.Items {
+		application := &applicationList.Items[i]
+		if !strings.HasPrefix(application.Namespace, r.NamespacePrefix) || !application.DeletionTimestamp.IsZero() {
+			continue
+		}
+
+		observed[client.ObjectKeyFromObject(application)] = true
+		r.revalidate(ctx, application)
+	}
+
+	for key := range r.observed {
+		if !observed[key] {
+			metrics.DeleteApplicationCompliance(key.Namespace, key.Name)
+		}
+	}
+	r.observed = observed
+
+	return nil
+}
+
+// revalidate re-validates the given Application and reports the result. Errors that are not conclusive violations,
+// such as a failure to reach the API server or the destination cluster, are logged and leave the last result in place.
+func (r *Revalidator) revalidate(ctx context.Context, application *argoprojv1alpha1.Application) {
+	logger := zap.New().WithName("revalidation").WithValues("namespace", application.Namespace, "name", application.Name)
+
+	clusterName := ""
+	if destServer, err := utils.ResolveDestinationServer(ctx, r.Client, application); err == nil {
+		clusterName = utils.ExtractClusterName(destServer)
+	}
+
+	validationErr := r.validate(ctx, application)
+	reason := decision.ReasonOf(validationErr)
+	if validationErr != nil && !conclusiveReasons[reason] {
+		logger.Error(validationErr, "Failed to re-validate Application, keeping the last result", "reason", reason)
+		return
+	}
+
+	compliant := validationErr == nil
+	if compliant {
+		reason = ""
+	}
+	metrics.ObserveApplicationCompliance(application.Namespace, application.Name, clusterName, string(reason), compliant)
+
+	original := application.DeepCopy()
+	previousStatus := application.Annotations[common.RevalidationStatusAnnotation]
+	previousMessage := application.Annotations[common.RevalidationMessageAnnotation]
+	if application.Annotations == nil {
+		application.Annotations = map[string]string{}
+	}
+
+	if compliant {
+		application.Annotations[common.RevalidationStatusAnnotation] = common.RevalidationStatusCompliant
+		delete(application.Annotations, common.RevalidationReasonAnnotation)
+		delete(application.Annotations, common.RevalidationMessageAnnotation)
+		if previousStatus == common.RevalidationStatusNonCompliant {
+			r.Recorder.Eventf(application, corev1.EventTypeNormal, ReasonRevalidationSucceeded,
+				"Application %s passed its re-validation again", application.Name)
+		}
+	} else {
+		application.Annotations[common.RevalidationStatusAnnotation] = common.RevalidationStatusNonCompliant
+		application.Annotations[common.RevalidationReasonAnnotation] = string(reason)
+		application.Annotations[common.RevalidationMessageAnnotation] = validationErr.Error()
+		if previousStatus != common.RevalidationStatusNonCompliant || previousMessage != validationErr.Error() {
+			logger.Info("Application failed its re-validation", "reason", reason, "violation", validationErr.Error())
+			r.Recorder.Eventf(application, corev1.EventTypeWarning, ReasonRevalidationFailed,
+				"Application %s failed its re-validation (%s): %s", application.Name, reason, validationErr.Error())
+		}
+		if r.DisableAutoSync {
+			r.disableAutoSync(ctx, application, clusterName)
+		}
+	}
+
+	if reflect.DeepEqual(original.Annotations, application.Annotations) && reflect.DeepEqual(original.Spec, application.Spec) {
+		return
+	}
+	if err := r.Client.Patch(ctx, application, client.MergeFrom(original)); err != nil {
+		logger.Error(err, "Failed to record the re-validation result on the Application")
+	}
+}
+
+// disableAutoSync disables the automated sync of the given non-compliant Application, unless its enforcement mode
+// is warn. The Application is patched by the caller.
+func (r *Revalidator) disableAutoSync(ctx context.Context, application *argoprojv1alpha1.Application, clusterName string) {
+	syncPolicy := application.Spec.SyncPolicy
+	if syncPolicy == nil || syncPolicy.Automated == nil || utils.IsAutoSyncDisabled(application) {
+		return
+	}
+
+	mode, err := utils.ResolveEnforcementMode(ctx, r.Client, application.Namespace, clusterName,
+		r.ValidatorConfig.EnforcementMode, r.ValidatorConfig.ClusterEnforcementModes)
+	if err != nil {
+		zap.New().WithName("revalidation").Error(err, "Failed to resolve enforcement mode, keeping automated sync",
+			"namespace", application.Namespace, "name", application.Name)
+		return
+	}
+	if mode == common.EnforcementModeWarn {
+		return
+	}
+
+	enabled := false
+	syncPolicy.Automated.Enabled = &enabled
+	r.Recorder.Eventf(application, corev1.EventTypeWarning, ReasonAutoSyncDisabled,
+		"Automated sync of Application %s disabled because it failed its re-validation", application.Name)
+}
@@ -0,0 +1,173 @@
+package revalidation
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	argoprojv1alpha1 "github.com/argoproj/argo-cd/v3/pkg/apis/application/v1alpha1"
+	"github.com/dana-team/application-rbac-validator/internal/common"
+	"github.com/dana-team/application-rbac-validator/internal/decision"
+	"github.com/dana-team/application-rbac-validator/internal/utils"
+	webhookargoprojv1alpha1 "github.com/dana-team/application-rbac-validator/internal/webhook/v1alpha1"
+	testutils "github.com/dana-team/application-rbac-validator/test/utils"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	sampleNamespace = "argocd-team-a"
+	sampleServer    = "https://api.my-cluster.example.com:6443"
+)
+
+// newAutoSyncApplication returns an Application of the sample namespace with automated sync.
+func newAutoSyncApplication(annotations map[string]string) *argoprojv1alpha1.Application {
+	application := testutils.GenerateTestApplication(sampleNamespace, sampleServer, "team-a")
+	application.Annotations = annotations
+	application.Spec.SyncPolicy = &argoprojv1alpha1.SyncPolicy{
+		Automated: &argoprojv1alpha1.SyncPolicyAutomated{Prune: true},
+	}
+	return application
+}
+
+// drainEvents returns the reasons of the events recorded so far.
+func drainEvents(recorder *record.FakeRecorder) []string {
+	var reasons []string
+	for {
+		select {
+		case event := <-recorder.Events:
+			reasons = append(reasons, strings.Fields(event)[1])
+		default:
+			return reasons
+		}
+	}
+}
+
+func TestRevalidateAll(t *testing.T) {
+	violation := decision.New(decision.ReasonDestinationUnauthorized, "spec.destination.namespace",
+		"no admin has access to namespace team-a")
+	nonCompliant := map[string]string{
+		common.RevalidationStatusAnnotation:  common.RevalidationStatusNonCompliant,
+		common.RevalidationReasonAnnotation:  string(decision.ReasonDestinationUnauthorized),
+		common.RevalidationMessageAnnotation: violation.Error(),
+	}
+
+	testCases := []struct {
+		name             string
+		annotations      map[string]string
+		namespaceLabels  map[string]string
+		validationErr    error
+		disableAutoSync  bool
+		expectedStatus   string
+		expectedAutoSync bool
+		expectedEvents   []string
+	}{
+		{
+			name:             "should annotate a compliant Application without events",
+			expectedStatus:   common.RevalidationStatusCompliant,
+			expectedAutoSync: true,
+		},
+		{
+			name:             "should annotate and warn about a non-compliant Application",
+			validationErr:    violation,
+			expectedStatus:   common.RevalidationStatusNonCompliant,
+			expectedAutoSync: true,
+			expectedEvents:   []string{ReasonRevalidationFailed},
+		},
+		{
+			name:             "should not warn again about the same violation",
+			annotations:      nonCompliant,
+			validationErr:    violation,
+			expectedStatus:   common.RevalidationStatusNonCompliant,
+			expectedAutoSync: true,
+		},
+		{
+			name:             "should report an Application passing its re-validation again",
+			annotations:      nonCompliant,
+			expectedStatus:   common.RevalidationStatusCompliant,
+			expectedAutoSync: true,
+			expectedEvents:   []string{ReasonRevalidationSucceeded},
+		},
+		{
+			name:             "should keep the last result when the validation fails with an internal error",
+			annotations:      nonCompliant,
+			validationErr:    errors.New("failed to list secrets"),
+			expectedStatus:   common.RevalidationStatusNonCompliant,
+			expectedAutoSync: true,
+		},
+		{
+			name:        "should keep the last result and the automated sync when the destination is unreachable",
+			annotations: map[string]string{common.RevalidationStatusAnnotation: common.RevalidationStatusCompliant},
+			validationErr: decision.Wrap(decision.ReasonDestinationUnreachable, "spec.destination.server",
+				errors.New("failed to build destination's cluster client")),
+			disableAutoSync:  true,
+			expectedStatus:   common.RevalidationStatusCompliant,
+			expectedAutoSync: true,
+		},
+		{
+			name:             "should disable the automated sync of a non-compliant Application",
+			validationErr:    violation,
+			disableAutoSync:  true,
+			expectedStatus:   common.RevalidationStatusNonCompliant,
+			expectedAutoSync: false,
+			expectedEvents:   []string{ReasonRevalidationFailed, ReasonAutoSyncDisabled},
+		},
+		{
+			name:             "should keep the automated sync of a non-compliant Application in warn mode",
+			namespaceLabels:  map[string]string{common.EnforcementModeLabel: common.EnforcementModeWarn},
+			validationErr:    violation,
+			disableAutoSync:  true,
+			expectedStatus:   common.RevalidationStatusNonCompliant,
+			expectedAutoSync: true,
+			expectedEvents:   []string{ReasonRevalidationFailed},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			application := newAutoSyncApplication(tc.annotations)
+			outOfScope := testutils.GenerateTestApplication("other-namespace", sampleServer, "team-a")
+			namespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: sampleNamespace, Labels: tc.namespaceLabels}}
+			k8sClient := testutils.NewFakeClient(application, outOfScope, namespace)
+			recorder := record.NewFakeRecorder(10)
+
+			revalidator := NewRevalidator(k8sClient, recorder, webhookargoprojv1alpha1.ValidatorConfig{}, "argocd-",
+				time.Hour, tc.disableAutoSync)
+			revalidator.validate = func(_ context.Context, _ *argoprojv1alpha1.Application) error {
+				return tc.validationErr
+			}
+
+			if err := revalidator.RevalidateAll(context.Background()); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			revalidated := &argoprojv1alpha1.Application{}
+			if err := k8sClient.Get(context.Background(), client.ObjectKeyFromObject(application), revalidated); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if status := revalidated.Annotations[common.RevalidationStatusAnnotation]; status != tc.expectedStatus {
+				t.Errorf("expected %v but got %v", tc.expectedStatus, status)
+			}
+			if autoSync := !utils.IsAutoSyncDisabled(revalidated); autoSync != tc.expectedAutoSync {
+				t.Errorf("expected %v but got %v", tc.expectedAutoSync, autoSync)
+			}
+
+			skipped := &argoprojv1alpha1.Application{}
+			if err := k8sClient.Get(context.Background(), client.ObjectKeyFromObject(outOfScope), skipped); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if _, ok := skipped.Annotations[common.RevalidationStatusAnnotation]; ok {
+				t.Errorf("expected Applications outside the namespace prefix to be skipped")
+			}
+
+			events := drainEvents(recorder)
+			if strings.Join(events, ",") != strings.Join(tc.expectedEvents, ",") {
+				t.Errorf("expected %v but got %v", tc.expectedEvents, events)
+			}
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package tokens
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Claims holds the time claims of a JWT bearer token. A zero time means the claim is not present.
+type Claims struct {
+	ExpiresAt time.Time
+	IssuedAt  time.Time
+}
+
+// ParseClaims reads the exp and iat claims of the given JWT without verifying its signature, which is left to the
+// destination cluster. It returns an error if the token is not a JWT, e.g. because it is a static token.
+func ParseClaims(token string) (Claims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return Claims{}, fmt.Errorf("token is not a JWT")
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+	if err != nil {
+		return Claims{}, fmt.Errorf("failed to decode token payload: %w", err)
+	}
+
+	var raw struct {
+		ExpiresAt *json.Number `json:"exp"`
+		IssuedAt  *json.Number `json:"iat"`
+	}
+	if err := json.Unmarshal(payload, &raw); err != nil {
+		return Claims{}, fmt.Errorf("failed to parse token payload: %w", err)
+	}
+
+	var claims Claims
+	if claims.ExpiresAt, err = parseNumericDate(raw.ExpiresAt); err != nil {
+		return Claims{}, fmt.Errorf("failed to parse exp claim: %w", err)
+	}
+	if claims.IssuedAt, err = parseNumericDate(raw.IssuedAt); err != nil {
+		return Claims{}, fmt.Errorf("failed to parse iat claim: %w", err)
+	}
+
+	return claims, nil
+}
+
+// parseNumericDate converts a JWT NumericDate, the number of seconds since the epoch, into a time.
+func parseNumericDate(value *json.Number) (time.Time, error) {
+	if value == nil {
+		return time.Time{}, nil
+	}
+
+	seconds, err := value.Float64()
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return time.Unix(int64(seconds), 0), nil
+}
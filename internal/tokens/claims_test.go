@@ -0,0 +1,58 @@
+package tokens
+
+import (
+	"encoding/base64"
+	"testing"
+	"time"
+)
+
+// newTestToken returns an unsigned JWT carrying the given payload.
+func newTestToken(payload string) string {
+	encode := base64.RawURLEncoding.EncodeToString
+	return encode([]byte(`{"alg":"RS256"}`)) + "." + encode([]byte(payload)) + ".signature"
+}
+
+func TestParseClaims(t *testing.T) {
+	testCases := []struct {
+		name          string
+		token         string
+		expected      Claims
+		expectedError bool
+	}{
+		{
+			name:  "should parse the exp and iat claims",
+			token: newTestToken(`{"exp":1767225600,"iat":1735689600,"sub":"system:serviceaccount:ci:validator"}`),
+			expected: Claims{
+				ExpiresAt: time.Unix(1767225600, 0),
+				IssuedAt:  time.Unix(1735689600, 0),
+			},
+		},
+		{
+			name:     "should leave missing claims empty",
+			token:    newTestToken(`{"iat":1735689600}`),
+			expected: Claims{IssuedAt: time.Unix(1735689600, 0)},
+		},
+		{
+			name:          "should reject a token that is not a JWT",
+			token:         "static-token",
+			expectedError: true,
+		},
+		{
+			name:          "should reject a payload that is not JSON",
+			token:         "header." + base64.RawURLEncoding.EncodeToString([]byte("not-json")) + ".signature",
+			expectedError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := ParseClaims(tc.token)
+			if (err != nil) != tc.expectedError {
+				t.Fatalf("expected error to be %v but got %v", tc.expectedError, err)
+			}
+			if !result.ExpiresAt.Equal(tc.expected.ExpiresAt) || !result.IssuedAt.Equal(tc.expected.IssuedAt) {
+				t.Errorf("expected %v but got %v", tc.expected, result)
+			}
+		})
+	}
+}
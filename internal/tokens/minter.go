@@ -0,0 +1,181 @@
+package tokens
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/dana-team/application-rbac-validator/internal/clusterclient"
+	"github.com/dana-team/application-rbac-validator/internal/utils"
+	"golang.org/x/sync/singleflight"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+)
+
+const (
+	// DefaultTokenExpiration is the default requested lifetime of the minted tokens.
+	DefaultTokenExpiration = time.Hour
+	// MinTokenExpiration is the shortest lifetime the TokenRequest API accepts.
+	MinTokenExpiration = 10 * time.Minute
+	// refreshRatio is the share of a minted token's lifetime after which it is refreshed.
+	refreshRatio = 0.8
+	// mintTimeout bounds a single TokenRequest.
+	mintTimeout = 10 * time.Second
+)
+
+// mintedToken is a cached token minted for a destination cluster ServiceAccount.
+type mintedToken struct {
+	token     string
+	expiresAt time.Time
+	refreshAt time.Time
+}
+
+// Minter is a clusterclient.Provider that accesses destination clusters as a dedicated validator ServiceAccount.
+// For credentials naming a ServiceAccount, it uses them as a bootstrap credential to mint a short-lived,
+// audience-bound token for that ServiceAccount through the TokenRequest API, caches it and refreshes it once most of
+// its lifetime has passed. Other credentials are passed to the wrapped Provider as they are.
+type Minter struct {
+	provider   clusterclient.Provider
+	audiences  []string
+	expiration time.Duration
+
+	mu       sync.Mutex
+	tokens   map[string]mintedToken
+	inflight singleflight.Group
+	now      func() time.Time
+	// bootstrapClient builds the client the TokenRequests are sent with.
+	bootstrapClient func(server string, credentials utils.ClusterCredentials) (kubernetes.Interface, error)
+}
+
+// NewMinter returns a Minter requesting tokens with the given audiences and lifetime, and building the clients of
+// the minted tokens through the given Provider.
+func NewMinter(provider clusterclient.Provider, audiences []string, expiration time.Duration) *Minter {
+	return &Minter{
+		provider:        provider,
+		audiences:       audiences,
+		expiration:      expiration,
+		tokens:          map[string]mintedToken{},
+		now:             time.Now,
+		bootstrapClient: utils.BuildClusterClient,
+	}
+}
+
+// ClientFor implements clusterclient.Provider.
+func (m *Minter) ClientFor(server string, credentials utils.ClusterCredentials) (kubernetes.Interface, error) {
+	if credentials.ServiceAccount.Name == "" {
+		return m.provider.ClientFor(server, credentials)
+	}
+
+	token, err := m.Token(server, credentials)
+	if err != nil {
+		return nil, err
+	}
+
+	return m.provider.ClientFor(server, utils.ClusterCredentials{
+		Token:    token,
+		CAData:   credentials.CAData,
+		Insecure: credentials.Insecure,
+	})
+}
+
+// Token returns a token of the ServiceAccount named by the given credentials. The cached token is returned until it
+// is due for a refresh. When a refresh fails, the cached token keeps being returned until it expires.
+func (m *Minter) Token(server string, credentials utils.ClusterCredentials) (string, error) {
+	logger := zap.New().WithName("tokens").WithValues("server", server, "serviceAccount", credentials.ServiceAccount)
+
+	key := cacheKey(server, credentials)
+
+	m.mu.Lock()
+	cached, ok := m.tokens[key]
+	m.mu.Unlock()
+
+	now := m.now()
+	if ok && now.Before(cached.refreshAt) {
+		return cached.token, nil
+	}
+
+	minted, err, _ := m.inflight.Do(key, func() (any, error) {
+		return m.mint(server, credentials)
+	})
+	if err != nil {
+		if ok && now.Before(cached.expiresAt) {
+			logger.Error(err, "Failed to refresh the ServiceAccount token, using the cached token until it expires",
+				"expiresAt", cached.expiresAt)
+			return cached.token, nil
+		}
+		return "", err
+	}
+
+	token := minted.(mintedToken)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.tokens[key] = token
+	for cachedKey, cachedToken := range m.tokens {
+		if !now.Before(cachedToken.expiresAt) {
+			delete(m.tokens, cachedKey)
+		}
+	}
+
+	return token.token, nil
+}
+
+// mint requests a new token for the ServiceAccount named by the given credentials, authenticating with the
+// credentials themselves.
+func (m *Minter) mint(server string, credentials utils.ClusterCredentials) (mintedToken, error) {
+	bootstrapCredentials := credentials
+	bootstrapCredentials.ServiceAccount = types.NamespacedName{}
+
+	client, err := m.bootstrapClient(server, bootstrapCredentials)
+	if err != nil {
+		return mintedToken{}, fmt.Errorf("failed to build bootstrap client for %s: %w", server, err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), mintTimeout)
+	defer cancel()
+
+	expirationSeconds := int64(m.expiration.Seconds())
+	request := &authenticationv1.TokenRequest{
+		Spec: authenticationv1.TokenRequestSpec{
+			Audiences:         m.audiences,
+			ExpirationSeconds: &expirationSeconds,
+		},
+	}
+
+	serviceAccount := credentials.ServiceAccount
+	issuedAt := m.now()
+	response, err := client.CoreV1().ServiceAccounts(serviceAccount.Namespace).CreateToken(ctx, serviceAccount.Name,
+		request, metav1.CreateOptions{})
+	if err != nil {
+		return mintedToken{}, fmt.Errorf("failed to mint a token for ServiceAccount %s on %s: %w", serviceAccount,
+			server, utils.WrapClusterConnectionError(server, err))
+	}
+
+	expiresAt := response.Status.ExpirationTimestamp.Time
+	return mintedToken{
+		token:     response.Status.Token,
+		expiresAt: expiresAt,
+		refreshAt: issuedAt.Add(time.Duration(float64(expiresAt.Sub(issuedAt)) * refreshRatio)),
+	}, nil
+}
+
+// cacheKey returns the key the token of the given server and credentials is cached under. It covers the bootstrap
+// credential, so a new token is minted once it is rotated.
+func cacheKey(server string, credentials utils.ClusterCredentials) string {
+	hash := sha256.New()
+	hash.Write([]byte(server))
+	hash.Write([]byte{0})
+	hash.Write([]byte(credentials.ServiceAccount.String()))
+	hash.Write([]byte{0})
+	hash.Write([]byte(credentials.Token))
+	hash.Write([]byte{0})
+	hash.Write(credentials.CertData)
+	return hex.EncodeToString(hash.Sum(nil))
+}
@@ -0,0 +1,154 @@
+package tokens
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/dana-team/application-rbac-validator/internal/utils"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+var sampleServiceAccount = types.NamespacedName{Namespace: "application-rbac-validator", Name: "validator"}
+
+// recordingProvider is a clusterclient.Provider recording the credentials it was asked for.
+type recordingProvider struct {
+	credentials []utils.ClusterCredentials
+}
+
+// ClientFor implements clusterclient.Provider.
+func (p *recordingProvider) ClientFor(_ string, credentials utils.ClusterCredentials) (kubernetes.Interface, error) {
+	p.credentials = append(p.credentials, credentials)
+	return fake.NewClientset(), nil
+}
+
+// newTestMinter returns a Minter with a controllable clock whose TokenRequests are answered by a fake client,
+// counting them and failing them while mintErr is set.
+func newTestMinter(now *time.Time, mints *int, mintErr *error) (*Minter, *recordingProvider) {
+	provider := &recordingProvider{}
+	minter := NewMinter(provider, []string{"https://kubernetes.default.svc"}, time.Hour)
+	minter.now = func() time.Time { return *now }
+	minter.bootstrapClient = func(_ string, _ utils.ClusterCredentials) (kubernetes.Interface, error) {
+		client := fake.NewClientset()
+		client.PrependReactor("create", "serviceaccounts", func(action k8stesting.Action) (bool, runtime.Object, error) {
+			if action.GetSubresource() != "token" {
+				return false, nil, nil
+			}
+			if *mintErr != nil {
+				return true, nil, *mintErr
+			}
+			*mints++
+			return true, &authenticationv1.TokenRequest{
+				Status: authenticationv1.TokenRequestStatus{
+					Token:               fmt.Sprintf("minted-token-%d", *mints),
+					ExpirationTimestamp: metav1.NewTime(now.Add(time.Hour)),
+				},
+			}, nil
+		})
+		return client, nil
+	}
+	return minter, provider
+}
+
+func TestMinterClientFor(t *testing.T) {
+	bootstrapCredentials := utils.ClusterCredentials{Token: sampleToken, ServiceAccount: sampleServiceAccount}
+
+	testCases := []struct {
+		name           string
+		steps          []time.Duration
+		credentials    []utils.ClusterCredentials
+		expectedMints  int
+		expectedTokens []string
+	}{
+		{
+			name:           "should pass credentials without a ServiceAccount through",
+			steps:          []time.Duration{0},
+			credentials:    []utils.ClusterCredentials{{Token: sampleToken}},
+			expectedMints:  0,
+			expectedTokens: []string{sampleToken},
+		},
+		{
+			name:           "should reuse the minted token until it is due for a refresh",
+			steps:          []time.Duration{0, 30 * time.Minute},
+			credentials:    []utils.ClusterCredentials{bootstrapCredentials, bootstrapCredentials},
+			expectedMints:  1,
+			expectedTokens: []string{"minted-token-1", "minted-token-1"},
+		},
+		{
+			name:           "should refresh the token once most of its lifetime has passed",
+			steps:          []time.Duration{0, 50 * time.Minute},
+			credentials:    []utils.ClusterCredentials{bootstrapCredentials, bootstrapCredentials},
+			expectedMints:  2,
+			expectedTokens: []string{"minted-token-1", "minted-token-2"},
+		},
+		{
+			name:  "should mint a new token when the bootstrap credential is rotated",
+			steps: []time.Duration{0, time.Minute},
+			credentials: []utils.ClusterCredentials{bootstrapCredentials,
+				{Token: "rotated-token", ServiceAccount: sampleServiceAccount}},
+			expectedMints:  2,
+			expectedTokens: []string{"minted-token-1", "minted-token-2"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			now := sampleNow
+			mints := 0
+			var mintErr error
+			minter, provider := newTestMinter(&now, &mints, &mintErr)
+
+			for i, step := range tc.steps {
+				now = now.Add(step)
+				if _, err := minter.ClientFor(sampleServer, tc.credentials[i]); err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+			}
+
+			if mints != tc.expectedMints {
+				t.Errorf("expected %d minted tokens but got %d", tc.expectedMints, mints)
+			}
+			for i, expectedToken := range tc.expectedTokens {
+				if provider.credentials[i].Token != expectedToken {
+					t.Errorf("expected token %q at request %d but got %q", expectedToken, i, provider.credentials[i].Token)
+				}
+				if provider.credentials[i].ServiceAccount.Name != "" {
+					t.Errorf("expected the credentials at request %d to not name a ServiceAccount", i)
+				}
+			}
+		})
+	}
+}
+
+func TestMinterRefreshFailure(t *testing.T) {
+	now := sampleNow
+	mints := 0
+	var mintErr error
+	minter, _ := newTestMinter(&now, &mints, &mintErr)
+	credentials := utils.ClusterCredentials{Token: sampleToken, ServiceAccount: sampleServiceAccount}
+
+	if _, err := minter.Token(sampleServer, credentials); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	mintErr = fmt.Errorf("connection refused")
+	now = now.Add(50 * time.Minute)
+	token, err := minter.Token(sampleServer, credentials)
+	if err != nil {
+		t.Fatalf("expected the cached token to be used while it is valid but got %v", err)
+	}
+	if token != "minted-token-1" {
+		t.Errorf("expected the cached token but got %q", token)
+	}
+
+	now = now.Add(20 * time.Minute)
+	if _, err := minter.Token(sampleServer, credentials); err == nil {
+		t.Errorf("expected an error once the cached token expired but got none")
+	}
+}
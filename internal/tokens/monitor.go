@@ -0,0 +1,303 @@
+package tokens
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dana-team/application-rbac-validator/internal/clusterclient"
+	"github.com/dana-team/application-rbac-validator/internal/common"
+	"github.com/dana-team/application-rbac-validator/internal/metrics"
+	"github.com/dana-team/application-rbac-validator/internal/utils"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+)
+
+const (
+	// DefaultProbeInterval is the default interval between two probes of the destination cluster tokens.
+	DefaultProbeInterval = 10 * time.Minute
+	// DefaultExpiryWarningThreshold is the default duration before the expiry of a token from which it is reported.
+	DefaultExpiryWarningThreshold = 7 * 24 * time.Hour
+	// probeTimeout bounds the probe of a single destination cluster.
+	probeTimeout = 10 * time.Second
+)
+
+// Event reasons emitted on the object holding the token of a destination cluster.
+const (
+	ReasonTokenExpired       = "TokenExpired"
+	ReasonTokenExpiringSoon  = "TokenExpiringSoon"
+	ReasonTokenInvalid       = "TokenInvalid"
+	ReasonTokenUnauthorized  = "TokenUnauthorized"
+	ReasonTokenRecovered     = "TokenRecovered"
+	ReasonInvalidCredentials = "InvalidCredentials"
+)
+
+// +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+
+// target is a destination cluster token to check, along with the object it is stored in. The token is not probed
+// when the server is empty.
+type target struct {
+	cluster     string
+	server      string
+	object      runtime.Object
+	credentials utils.ClusterCredentials
+}
+
+// Monitor periodically checks the tokens of the destination clusters, so their rotation can be planned before they
+// expire. It reads the exp and iat claims of JWT tokens and probes each token with a SelfSubjectAccessReview, and
+// reports the results as metrics and as Events on the Secret or ConfigMap holding the token.
+type Monitor struct {
+	Client                 client.Client
+	ClusterClients         clusterclient.Provider
+	Recorder               record.EventRecorder
+	ServerUrlDomain        string
+	Interval               time.Duration
+	ExpiryWarningThreshold time.Duration
+
+	mu       sync.Mutex
+	valid    map[string]bool
+	observed map[string]bool
+	now      func() time.Time
+}
+
+// NewMonitor returns a Monitor probing the destination cluster tokens every interval.
+func NewMonitor(k8sClient client.Client, clusterClients clusterclient.Provider, recorder record.EventRecorder,
+	serverUrlDomain string, interval, expiryWarningThreshold time.Duration) *Monitor {
+	return &Monitor{
+		Client:                 k8sClient,
+		ClusterClients:         clusterClients,
+		Recorder:               recorder,
+		ServerUrlDomain:        serverUrlDomain,
+		Interval:               interval,
+		ExpiryWarningThreshold: expiryWarningThreshold,
+		valid:                  map[string]bool{},
+		observed:               map[string]bool{},
+		now:                    time.Now,
+	}
+}
+
+// Start checks the tokens right away and then every interval until the context is done. It implements
+// manager.Runnable.
+func (m *Monitor) Start(ctx context.Context) error {
+	logger := zap.New().WithName("tokens")
+
+	ticker := time.NewTicker(m.Interval)
+	defer ticker.Stop()
+
+	for {
+		if err := m.CheckAll(ctx); err != nil {
+			logger.Error(err, "Failed to check destination cluster tokens")
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable, so a single replica probes the destination
+// clusters and emits Events.
+func (m *Monitor) NeedLeaderElection() bool {
+	return true
+}
+
+// CheckAll checks the token of every destination cluster configured inside the webhook's namespace, and deletes the
+// metrics of the clusters that are no longer configured.
+func (m *Monitor) CheckAll(ctx context.Context) error {
+	targets, err := m.listTargets(ctx)
+	if err != nil {
+		return err
+	}
+
+	observed := map[string]bool{}
+	for _, t := range targets {
+		observed[t.cluster] = true
+		m.check(ctx, t)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for cluster := range m.observed {
+		if !observed[cluster] {
+			metrics.DeleteDestinationTokenMetrics(cluster)
+			delete(m.valid, cluster)
+		}
+	}
+	m.observed = observed
+
+	return nil
+}
+
+// listTargets returns the tokens stored in the credentials Secrets inside the webhook's namespace, followed by the
+// tokens of the other clusters stored in the cluster tokens ConfigMap.
+func (m *Monitor) listTargets(ctx context.Context) ([]target, error) {
+	namespace, err := utils.GetCurrentNamespace()
+	if err != nil {
+		return nil, err
+	}
+
+	secretList := &corev1.SecretList{}
+	labelSelector := client.MatchingLabels{common.ClusterCredentialsLabelKey: common.LabelValueTrue}
+	if err := m.Client.List(ctx, secretList, client.InNamespace(namespace), labelSelector); err != nil {
+		return nil, fmt.Errorf("failed to list cluster credentials secrets in namespace %s: %w", namespace, err)
+	}
+
+	var targets []target
+	seen := map[string]bool{}
+	for i := range secretList.Items {
+		secret := &secretList.Items[i]
+		server := strings.TrimSuffix(string(secret.Data[common.ClusterCredentialsServerKey]), "/")
+		cluster := utils.ExtractClusterName(server)
+
+		credentials, err := utils.ClusterCredentialsFromSecret(secret)
+		if err != nil {
+			m.Recorder.Event(secret, corev1.EventTypeWarning, ReasonInvalidCredentials, err.Error())
+			continue
+		}
+
+		seen[cluster] = true
+		targets = append(targets, target{cluster: cluster, server: server, object: secret, credentials: credentials})
+	}
+
+	configMap := &corev1.ConfigMap{}
+	err = m.Client.Get(ctx, client.ObjectKey{Namespace: namespace, Name: common.ClusterTokensConfigMapName}, configMap)
+	if apierrors.IsNotFound(err) {
+		return targets, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get ConfigMap %s/%s: %w", namespace, common.ClusterTokensConfigMapName, err)
+	}
+
+	for key := range configMap.Data {
+		if !strings.HasSuffix(key, common.ClusterTokenConfigMapKeySuffix) {
+			continue
+		}
+
+		cluster, ok := m.clusterFromConfigMapKey(key)
+		if seen[cluster] {
+			continue
+		}
+		seen[cluster] = true
+
+		if !ok {
+			targets = append(targets, target{cluster: cluster, object: configMap,
+				credentials: utils.ClusterCredentials{Token: strings.TrimSpace(configMap.Data[key])}})
+			continue
+		}
+
+		server := utils.BuildServerUrl(cluster, m.ServerUrlDomain)
+		credentials, err := utils.FetchClusterCredentials(ctx, m.Client, "", namespace, server)
+		if err != nil {
+			m.Recorder.Event(configMap, corev1.EventTypeWarning, ReasonInvalidCredentials, err.Error())
+			continue
+		}
+
+		targets = append(targets, target{cluster: cluster, server: server, object: configMap, credentials: credentials})
+	}
+
+	return targets, nil
+}
+
+// clusterFromConfigMapKey returns the name of the cluster whose token is stored under the given key of the cluster
+// tokens ConfigMap. It returns false, along with the file safe server URL, if the server URL of the key is not built
+// from a cluster name and the server URL domain, in which case the server cannot be probed.
+func (m *Monitor) clusterFromConfigMapKey(key string) (string, bool) {
+	fileSafeServerURL := strings.TrimSuffix(key, common.ClusterTokenConfigMapKeySuffix)
+	domainSuffix := utils.FormatFileSafeServerURL(utils.BuildServerUrl("", m.ServerUrlDomain))
+
+	cluster, ok := strings.CutSuffix(fileSafeServerURL, domainSuffix)
+	if !ok || cluster == "" {
+		return fileSafeServerURL, false
+	}
+
+	return cluster, true
+}
+
+// check reports the lifetime of the token of the given target and probes it against its destination cluster.
+func (m *Monitor) check(ctx context.Context, t target) {
+	logger := zap.New().WithName("tokens").WithValues("cluster", t.cluster)
+
+	claims, err := ParseClaims(t.credentials.Token)
+	if err != nil {
+		logger.V(1).Info("Token carries no readable claims", "reason", err.Error())
+	}
+	metrics.ObserveDestinationTokenLifetime(t.cluster, claims.ExpiresAt, claims.IssuedAt)
+
+	now := m.now()
+	switch {
+	case claims.ExpiresAt.IsZero():
+	case !claims.ExpiresAt.After(now):
+		m.Recorder.Eventf(t.object, corev1.EventTypeWarning, ReasonTokenExpired,
+			"Token of destination cluster %s expired at %s", t.cluster, claims.ExpiresAt.UTC().Format(time.RFC3339))
+	case claims.ExpiresAt.Sub(now) < m.ExpiryWarningThreshold:
+		m.Recorder.Eventf(t.object, corev1.EventTypeWarning, ReasonTokenExpiringSoon,
+			"Token of destination cluster %s expires at %s, rotate it before then", t.cluster,
+			claims.ExpiresAt.UTC().Format(time.RFC3339))
+	}
+
+	if t.server == "" {
+		return
+	}
+
+	reason, err := m.probe(ctx, t)
+	valid := err == nil
+	metrics.ObserveDestinationTokenValidity(t.cluster, valid)
+
+	m.mu.Lock()
+	wasValid, known := m.valid[t.cluster]
+	m.valid[t.cluster] = valid
+	m.mu.Unlock()
+
+	if !valid {
+		logger.Info("Destination cluster token probe failed", "reason", err.Error())
+		m.Recorder.Eventf(t.object, corev1.EventTypeWarning, reason,
+			"Token of destination cluster %s failed its probe: %s", t.cluster, err.Error())
+		return
+	}
+	if known && !wasValid {
+		m.Recorder.Eventf(t.object, corev1.EventTypeNormal, ReasonTokenRecovered,
+			"Token of destination cluster %s passed its probe again", t.cluster)
+	}
+}
+
+// probe creates a SelfSubjectAccessReview on the destination cluster of the given target, asking whether its token
+// may create the SubjectAccessReviews the webhook relies on. It returns the Event reason matching the failure.
+func (m *Monitor) probe(ctx context.Context, t target) (string, error) {
+	clusterClient, err := m.ClusterClients.ClientFor(t.server, t.credentials)
+	if err != nil {
+		return ReasonTokenInvalid, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, probeTimeout)
+	defer cancel()
+
+	review := &authorizationv1.SelfSubjectAccessReview{
+		Spec: authorizationv1.SelfSubjectAccessReviewSpec{
+			ResourceAttributes: &authorizationv1.ResourceAttributes{
+				Group:    authorizationv1.GroupName,
+				Resource: "subjectaccessreviews",
+				Verb:     "create",
+			},
+		},
+	}
+	response, err := clusterClient.AuthorizationV1().SelfSubjectAccessReviews().Create(ctx, review, metav1.CreateOptions{})
+	if err != nil {
+		return ReasonTokenInvalid, utils.WrapClusterConnectionError(t.cluster, err)
+	}
+	if !response.Status.Allowed {
+		return ReasonTokenUnauthorized, fmt.Errorf("token is not allowed to create subjectaccessreviews")
+	}
+
+	return "", nil
+}
@@ -0,0 +1,199 @@
+package tokens
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dana-team/application-rbac-validator/internal/clusterclient"
+	"github.com/dana-team/application-rbac-validator/internal/common"
+	"github.com/dana-team/application-rbac-validator/internal/utils"
+	testutils "github.com/dana-team/application-rbac-validator/test/utils"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	sampleNamespace = "application-rbac-validator"
+	sampleDomain    = "example.com"
+	sampleServer    = "https://api.my-cluster.example.com:6443"
+	sampleToken     = "static-token"
+)
+
+var sampleNow = time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// newProbeClient returns a destination cluster client answering SelfSubjectAccessReviews with the given result.
+func newProbeClient(allowed bool, err error) *fake.Clientset {
+	clusterClient := fake.NewClientset()
+	clusterClient.PrependReactor("create", "selfsubjectaccessreviews", func(_ k8stesting.Action) (bool, runtime.Object, error) {
+		if err != nil {
+			return true, nil, err
+		}
+		return true, &authorizationv1.SelfSubjectAccessReview{
+			Status: authorizationv1.SubjectAccessReviewStatus{Allowed: allowed},
+		}, nil
+	})
+	return clusterClient
+}
+
+// newCredentialsSecret returns a credentials Secret of the sample server holding the given token.
+func newCredentialsSecret(token string) *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "application-rbac-validator-cluster-my-cluster",
+			Namespace: sampleNamespace,
+			Labels: map[string]string{
+				common.ClusterCredentialsLabelKey: common.LabelValueTrue,
+				common.ClusterServerHashLabelKey:  utils.ServerURLHash(sampleServer),
+			},
+		},
+		Data: map[string][]byte{
+			common.ClusterCredentialsServerKey: []byte(sampleServer),
+			common.ClusterCredentialsTokenKey:  []byte(token),
+		},
+	}
+}
+
+// tokenExpiringAt returns a JWT expiring at the given time.
+func tokenExpiringAt(expiresAt time.Time) string {
+	return newTestToken(fmt.Sprintf(`{"exp":%d,"iat":%d}`, expiresAt.Unix(), sampleNow.Add(-time.Hour).Unix()))
+}
+
+// drainEvents returns the reasons of the events recorded so far.
+func drainEvents(recorder *record.FakeRecorder) []string {
+	var reasons []string
+	for {
+		select {
+		case event := <-recorder.Events:
+			reasons = append(reasons, strings.Fields(event)[1])
+		default:
+			return reasons
+		}
+	}
+}
+
+// setWebhookNamespace points the webhook's namespace file at a temporary file holding the sample namespace.
+func setWebhookNamespace(t *testing.T) {
+	t.Helper()
+	namespacePath := filepath.Join(t.TempDir(), "namespace")
+	if err := os.WriteFile(namespacePath, []byte(sampleNamespace), 0644); err != nil {
+		t.Fatalf("failed to write namespace file: %v", err)
+	}
+	originalPath := common.WebhookNamespacePath
+	common.WebhookNamespacePath = namespacePath
+	t.Cleanup(func() { common.WebhookNamespacePath = originalPath })
+}
+
+func TestMonitorCheckAll(t *testing.T) {
+	setWebhookNamespace(t)
+
+	testCases := []struct {
+		name           string
+		existingObjs   []client.Object
+		probeAllowed   bool
+		probeError     error
+		expectedEvents []string
+	}{
+		{
+			name:         "should not emit events for a valid token far from its expiry",
+			existingObjs: []client.Object{newCredentialsSecret(tokenExpiringAt(sampleNow.Add(30 * 24 * time.Hour)))},
+			probeAllowed: true,
+		},
+		{
+			name:         "should not emit events for a valid static token",
+			existingObjs: []client.Object{newCredentialsSecret(sampleToken)},
+			probeAllowed: true,
+		},
+		{
+			name:           "should warn about a token expiring within the threshold",
+			existingObjs:   []client.Object{newCredentialsSecret(tokenExpiringAt(sampleNow.Add(24 * time.Hour)))},
+			probeAllowed:   true,
+			expectedEvents: []string{ReasonTokenExpiringSoon},
+		},
+		{
+			name:           "should warn about an expired token failing its probe",
+			existingObjs:   []client.Object{newCredentialsSecret(tokenExpiringAt(sampleNow.Add(-time.Hour)))},
+			probeError:     fmt.Errorf("Unauthorized"),
+			expectedEvents: []string{ReasonTokenExpired, ReasonTokenInvalid},
+		},
+		{
+			name:           "should warn about a token that may not create access reviews",
+			existingObjs:   []client.Object{newCredentialsSecret(sampleToken)},
+			probeAllowed:   false,
+			expectedEvents: []string{ReasonTokenUnauthorized},
+		},
+		{
+			name: "should probe tokens of the cluster tokens ConfigMap",
+			existingObjs: []client.Object{&corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: common.ClusterTokensConfigMapName, Namespace: sampleNamespace},
+				Data: map[string]string{
+					utils.FormatFileSafeServerURL(sampleServer) + common.ClusterTokenConfigMapKeySuffix: sampleToken,
+				},
+			}},
+			probeError:     fmt.Errorf("Unauthorized"),
+			expectedEvents: []string{ReasonTokenInvalid},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			recorder := record.NewFakeRecorder(10)
+			monitor := NewMonitor(testutils.NewFakeClient(tc.existingObjs...),
+				clusterclient.NewStaticProvider(newProbeClient(tc.probeAllowed, tc.probeError)), recorder,
+				sampleDomain, DefaultProbeInterval, DefaultExpiryWarningThreshold)
+			monitor.now = func() time.Time { return sampleNow }
+
+			if err := monitor.CheckAll(context.Background()); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			events := drainEvents(recorder)
+			if strings.Join(events, ",") != strings.Join(tc.expectedEvents, ",") {
+				t.Errorf("expected events %v but got %v", tc.expectedEvents, events)
+			}
+		})
+	}
+}
+
+func TestMonitorRecovery(t *testing.T) {
+	setWebhookNamespace(t)
+
+	provider := &switchingProvider{err: fmt.Errorf("Unauthorized")}
+	recorder := record.NewFakeRecorder(10)
+	monitor := NewMonitor(testutils.NewFakeClient(newCredentialsSecret(sampleToken)), provider, recorder,
+		sampleDomain, DefaultProbeInterval, DefaultExpiryWarningThreshold)
+
+	if err := monitor.CheckAll(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	provider.err = nil
+	if err := monitor.CheckAll(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []string{ReasonTokenInvalid, ReasonTokenRecovered}
+	if events := drainEvents(recorder); strings.Join(events, ",") != strings.Join(expected, ",") {
+		t.Errorf("expected events %v but got %v", expected, events)
+	}
+}
+
+// switchingProvider is a clusterclient.Provider whose probe result can be changed between checks.
+type switchingProvider struct {
+	err error
+}
+
+// ClientFor implements clusterclient.Provider.
+func (p *switchingProvider) ClientFor(_ string, _ utils.ClusterCredentials) (kubernetes.Interface, error) {
+	return newProbeClient(true, p.err), nil
+}
@@ -0,0 +1,96 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// TracerName is the name of the tracer the spans of the validator are started with.
+	TracerName = "github.com/dana-team/application-rbac-validator"
+	// ServiceName is the service name the spans of the validator are exported under.
+	ServiceName = "application-rbac-validator"
+	// shutdownTimeout is the longest the remaining spans are flushed for when the manager stops.
+	shutdownTimeout = 10 * time.Second
+)
+
+// Provider exports the spans of the validator over OTLP/gRPC, and flushes the remaining spans when the manager
+// stops.
+type Provider struct {
+	provider *sdktrace.TracerProvider
+}
+
+// NewProvider returns a Provider exporting to the given OTLP/gRPC endpoint, and installs it as the global tracer
+// provider along with the W3C trace context propagator. Until a Provider is installed, spans are not recorded.
+func NewProvider(ctx context.Context, endpoint string, insecure bool) (*Provider, error) {
+	options := []otlptracegrpc.Option{otlptracegrpc.WithEndpoint(endpoint)}
+	if insecure {
+		options = append(options, otlptracegrpc.WithInsecure())
+	}
+
+	exporter, err := otlptracegrpc.New(ctx, options...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create OTLP trace exporter for %s: %w", endpoint, err)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(resource.NewSchemaless(attribute.String("service.name", ServiceName))),
+	)
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	return &Provider{provider: provider}, nil
+}
+
+// Start waits until the context is done, and then flushes the remaining spans and shuts the exporter down. It
+// implements manager.Runnable.
+func (p *Provider) Start(ctx context.Context) error {
+	<-ctx.Done()
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+
+	if err := p.provider.Shutdown(shutdownCtx); err != nil {
+		return fmt.Errorf("failed to shut the tracer provider down: %w", err)
+	}
+
+	return nil
+}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable, as every replica records spans.
+func (p *Provider) NeedLeaderElection() bool {
+	return false
+}
+
+// Start starts a span with the given name and attributes, returning a copy of the given context carrying it.
+func Start(ctx context.Context, name string, attributes ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(TracerName).Start(ctx, name, trace.WithAttributes(attributes...))
+}
+
+// End ends the given span, recording the given error on it if it is not nil.
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// WrapTransport wraps the given transport so every request it sends is traced in a span of the request's context,
+// and carries that span to the server in its headers.
+func WrapTransport(rt http.RoundTripper) http.RoundTripper {
+	return otelhttp.NewTransport(rt)
+}
@@ -0,0 +1,85 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// setupRecorder installs a tracer provider recording the ended spans for the duration of the test.
+func setupRecorder(t *testing.T) *tracetest.SpanRecorder {
+	recorder := tracetest.NewSpanRecorder()
+	previousProvider, previousPropagator := otel.GetTracerProvider(), otel.GetTextMapPropagator()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() {
+		otel.SetTracerProvider(previousProvider)
+		otel.SetTextMapPropagator(previousPropagator)
+	})
+
+	return recorder
+}
+
+func TestEnd(t *testing.T) {
+	recorder := setupRecorder(t)
+
+	_, span := Start(context.Background(), "succeeded")
+	End(span, nil)
+	_, span = Start(context.Background(), "failed")
+	End(span, errors.New("failed to fetch cluster credentials"))
+
+	spans := recorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("expected %v but got %v", 2, len(spans))
+	}
+	if spans[0].Status().Code != codes.Unset {
+		t.Errorf("expected %v but got %v", codes.Unset, spans[0].Status().Code)
+	}
+	if spans[1].Status().Code != codes.Error || spans[1].Status().Description != "failed to fetch cluster credentials" {
+		t.Errorf("expected an error status but got %v", spans[1].Status())
+	}
+	if len(spans[1].Events()) != 1 {
+		t.Errorf("expected the error to be recorded but got %v", spans[1].Events())
+	}
+}
+
+func TestWrapTransport(t *testing.T) {
+	recorder := setupRecorder(t)
+
+	var traceParent string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceParent = r.Header.Get("traceparent")
+	}))
+	defer server.Close()
+
+	ctx, span := Start(context.Background(), "access_review")
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	response, err := (&http.Client{Transport: WrapTransport(http.DefaultTransport)}).Do(request)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_ = response.Body.Close()
+	End(span, nil)
+
+	spans := recorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("expected %v but got %v", 2, len(spans))
+	}
+	if spans[0].Parent().SpanID() != span.SpanContext().SpanID() {
+		t.Errorf("expected the request span to be a child of the stage span")
+	}
+	if traceParent == "" || spans[0].SpanContext().TraceID() != span.SpanContext().TraceID() {
+		t.Errorf("expected the trace context to be propagated but got %q", traceParent)
+	}
+}
@@ -0,0 +1,384 @@
+package utils
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"reflect"
+	"regexp"
+	"strings"
+
+	argoprojv1alpha1 "github.com/argoproj/argo-cd/v3/pkg/apis/application/v1alpha1"
+	"github.com/dana-team/application-rbac-validator/internal/common"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+)
+
+// templatePlaceholderPattern matches both fasttemplate ("{{server}}") and simple Go template ("{{ .server }}")
+// placeholders, capturing the referenced parameter key.
+var templatePlaceholderPattern = regexp.MustCompile(`\{\{-?\s*\.?([A-Za-z0-9_.\-]+)\s*-?\}\}`)
+
+// IsNotApplicationSetSpecUpdate checks if the only difference between the old and new ApplicationSet objects is
+// outside their Spec. It returns true if the spec has not changed, false otherwise.
+func IsNotApplicationSetSpecUpdate(oldAppSet, newAppSet *argoprojv1alpha1.ApplicationSet) bool {
+	return reflect.DeepEqual(oldAppSet.Spec, newAppSet.Spec)
+}
+
+// HasTemplatePlaceholders returns a bool indicating whether the given value still contains template placeholders.
+func HasTemplatePlaceholders(value string) bool {
+	return strings.Contains(value, "{{")
+}
+
+// RenderTemplateString replaces the placeholders in the given value with the matching parameters.
+// It returns false if any placeholder could not be resolved from the parameters.
+func RenderTemplateString(value string, params map[string]string) (string, bool) {
+	resolved := true
+	rendered := templatePlaceholderPattern.ReplaceAllStringFunc(value, func(placeholder string) string {
+		key := templatePlaceholderPattern.FindStringSubmatch(placeholder)[1]
+		param, ok := params[key]
+		if !ok {
+			resolved = false
+			return placeholder
+		}
+		return param
+	})
+
+	return rendered, resolved && !HasTemplatePlaceholders(rendered)
+}
+
+// flattenParams flattens a nested generator element into dot separated parameter keys, the same way they are
+// referenced from an ApplicationSet template (e.g. "values.cluster").
+func flattenParams(prefix string, value any, params map[string]string) {
+	switch typed := value.(type) {
+	case map[string]any:
+		for key, nested := range typed {
+			if prefix != "" {
+				key = prefix + "." + key
+			}
+			flattenParams(key, nested, params)
+		}
+	case string:
+		params[prefix] = typed
+	case nil:
+		params[prefix] = ""
+	default:
+		params[prefix] = fmt.Sprint(typed)
+	}
+}
+
+// ListGeneratorParams returns the parameter sets of the static elements of the given List generator.
+func ListGeneratorParams(generator *argoprojv1alpha1.ListGenerator) ([]map[string]string, error) {
+	paramSets := make([]map[string]string, 0, len(generator.Elements))
+	for _, element := range generator.Elements {
+		var raw map[string]any
+		if err := json.Unmarshal(element.Raw, &raw); err != nil {
+			return nil, fmt.Errorf("failed to parse list generator element: %w", err)
+		}
+
+		params := map[string]string{}
+		flattenParams("", raw, params)
+		paramSets = append(paramSets, params)
+	}
+
+	return paramSets, nil
+}
+
+// ClusterGeneratorParams returns the parameter sets the given Cluster generator produces from the Argo CD cluster
+// secrets inside the given namespace. The local cluster is included when no selector is set, as Argo CD does.
+func ClusterGeneratorParams(ctx context.Context, k8sClient client.Client, namespace string,
+	generator *argoprojv1alpha1.ClusterGenerator) ([]map[string]string, error) {
+	selector, err := metav1.LabelSelectorAsSelector(&generator.Selector)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse cluster generator selector: %w", err)
+	}
+
+	secretList := &corev1.SecretList{}
+	labelSelector := client.MatchingLabels{
+		common.ArgoCDSecretTypeLabelKey: common.ArgoCDSecretTypeClusterValue,
+	}
+	if err := k8sClient.List(ctx, secretList, client.InNamespace(namespace), labelSelector); err != nil {
+		return nil, fmt.Errorf("failed to list cluster secrets in namespace %s: %w", namespace, err)
+	}
+
+	var paramSets []map[string]string
+	if selector.Empty() {
+		paramSets = append(paramSets, clusterParams(common.InClusterValues[0], common.InClusterServerUrl, nil, nil,
+			generator.Values))
+	}
+
+	for _, secret := range secretList.Items {
+		if !selector.Matches(labels.Set(secret.Labels)) {
+			continue
+		}
+		paramSets = append(paramSets, clusterParams(string(secret.Data["name"]), string(secret.Data["server"]),
+			secret.Labels, secret.Annotations, generator.Values))
+	}
+
+	return paramSets, nil
+}
+
+// clusterParams builds the parameter set of a single cluster the way the Argo CD Cluster generator exposes it.
+func clusterParams(name, server string, secretLabels, secretAnnotations, values map[string]string) map[string]string {
+	params := map[string]string{
+		"name":           name,
+		"nameNormalized": strings.ReplaceAll(strings.ToLower(name), "_", "-"),
+		"server":         server,
+	}
+	for key, value := range secretLabels {
+		params["metadata.labels."+key] = value
+	}
+	for key, value := range secretAnnotations {
+		params["metadata.annotations."+key] = value
+	}
+	for key, value := range values {
+		params["values."+key] = value
+	}
+
+	return params
+}
+
+// GeneratedApplication is an Application an ApplicationSet generates, along with the template it was rendered from.
+type GeneratedApplication struct {
+	*argoprojv1alpha1.Application
+	// TemplateField is the path of the template the Application was rendered from, e.g. "spec.template" or
+	// "spec.generators[0].list.template" for a generator's template override.
+	TemplateField string
+}
+
+// renderGeneratedApplication renders the Application the given template would generate for the given parameters,
+// applying the template patch of the ApplicationSet if it has one. It returns false if the name or destination of the
+// Application could not be resolved statically.
+func renderGeneratedApplication(appSet *argoprojv1alpha1.ApplicationSet, template argoprojv1alpha1.ApplicationSetTemplate,
+	params map[string]string) (*argoprojv1alpha1.Application, bool, error) {
+	name, nameResolved := RenderTemplateString(template.Name, params)
+	server, serverResolved := RenderTemplateString(template.Spec.Destination.Server, params)
+	destName, destNameResolved := RenderTemplateString(template.Spec.Destination.Name, params)
+	namespace, namespaceResolved := RenderTemplateString(template.Spec.Destination.Namespace, params)
+	if !nameResolved || !serverResolved || !destNameResolved || !namespaceResolved {
+		return nil, false, nil
+	}
+
+	spec := template.Spec.DeepCopy()
+	spec.Destination = argoprojv1alpha1.ApplicationDestination{
+		Server:    server,
+		Name:      destName,
+		Namespace: namespace,
+	}
+
+	application := &argoprojv1alpha1.Application{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Namespace:   appSet.Namespace,
+			Labels:      renderTemplateMap(template.Labels, params),
+			Annotations: renderTemplateMap(template.Annotations, params),
+		},
+		Spec: *spec,
+	}
+	if appSet.Spec.TemplatePatch == nil {
+		return application, true, nil
+	}
+
+	return applyTemplatePatch(application, *appSet.Spec.TemplatePatch, params)
+}
+
+// applyTemplatePatch renders the given template patch with the given parameters and applies it to the given
+// Application as a strategic merge patch, the way Argo CD does. It returns false if the patch could not be resolved
+// statically.
+func applyTemplatePatch(application *argoprojv1alpha1.Application, templatePatch string,
+	params map[string]string) (*argoprojv1alpha1.Application, bool, error) {
+	renderedPatch, resolved := RenderTemplateString(templatePatch, params)
+	if !resolved {
+		return nil, false, nil
+	}
+
+	patch, err := yaml.YAMLToJSON([]byte(renderedPatch))
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to parse the template patch of Application %s: %w", application.Name, err)
+	}
+	original, err := json.Marshal(application)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to encode Application %s: %w", application.Name, err)
+	}
+	patched, err := strategicpatch.StrategicMergePatch(original, patch, argoprojv1alpha1.Application{})
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to apply the template patch to Application %s: %w", application.Name, err)
+	}
+
+	patchedApplication := &argoprojv1alpha1.Application{}
+	if err := json.Unmarshal(patched, patchedApplication); err != nil {
+		return nil, false, fmt.Errorf("failed to decode the patched Application %s: %w", application.Name, err)
+	}
+	patchedApplication.Namespace = application.Namespace
+
+	return patchedApplication, true, nil
+}
+
+// renderTemplateMap renders the keys and values of the given template labels or annotations, skipping the entries
+// that cannot be resolved from the parameters.
+func renderTemplateMap(values map[string]string, params map[string]string) map[string]string {
+	if len(values) == 0 {
+		return nil
+	}
+
+	rendered := make(map[string]string, len(values))
+	for key, value := range values {
+		renderedKey, keyResolved := RenderTemplateString(key, params)
+		renderedValue, valueResolved := RenderTemplateString(value, params)
+		if keyResolved && valueResolved {
+			rendered[renderedKey] = renderedValue
+		}
+	}
+
+	return rendered
+}
+
+// generatorTemplate returns the template used by a generator, applying the generator's own name, labels,
+// annotations and destination override on top of the ApplicationSet's template. The labels and annotations of the
+// override are merged into the template's, taking precedence over them.
+func generatorTemplate(appSet *argoprojv1alpha1.ApplicationSet,
+	override argoprojv1alpha1.ApplicationSetTemplate) argoprojv1alpha1.ApplicationSetTemplate {
+	template := *appSet.Spec.Template.DeepCopy()
+	if override.Name != "" {
+		template.Name = override.Name
+	}
+	if len(override.Labels) > 0 {
+		template.Labels = mergeTemplateMap(template.Labels, override.Labels)
+	}
+	if len(override.Annotations) > 0 {
+		template.Annotations = mergeTemplateMap(template.Annotations, override.Annotations)
+	}
+	if override.Spec.Destination.Server != "" {
+		template.Spec.Destination.Server = override.Spec.Destination.Server
+	}
+	if override.Spec.Destination.Name != "" {
+		template.Spec.Destination.Name = override.Spec.Destination.Name
+	}
+	if override.Spec.Destination.Namespace != "" {
+		template.Spec.Destination.Namespace = override.Spec.Destination.Namespace
+	}
+
+	return template
+}
+
+// mergeTemplateMap returns the given template labels or annotations overridden by the given ones.
+func mergeTemplateMap(values, overrides map[string]string) map[string]string {
+	merged := make(map[string]string, len(values)+len(overrides))
+	maps.Copy(merged, values)
+	maps.Copy(merged, overrides)
+
+	return merged
+}
+
+// generatorOverride returns the template override of the given generator and the path of its field inside the
+// generator.
+func generatorOverride(generator argoprojv1alpha1.ApplicationSetGenerator) (argoprojv1alpha1.ApplicationSetTemplate, string) {
+	switch {
+	case generator.List != nil:
+		return generator.List.Template, "list.template"
+	case generator.Clusters != nil:
+		return generator.Clusters.Template, "clusters.template"
+	case generator.Git != nil:
+		return generator.Git.Template, "git.template"
+	case generator.SCMProvider != nil:
+		return generator.SCMProvider.Template, "scmProvider.template"
+	case generator.ClusterDecisionResource != nil:
+		return generator.ClusterDecisionResource.Template, "clusterDecisionResource.template"
+	case generator.PullRequest != nil:
+		return generator.PullRequest.Template, "pullRequest.template"
+	case generator.Matrix != nil:
+		return generator.Matrix.Template, "matrix.template"
+	case generator.Merge != nil:
+		return generator.Merge.Template, "merge.template"
+	case generator.Plugin != nil:
+		return generator.Plugin.Template, "plugin.template"
+	default:
+		return argoprojv1alpha1.ApplicationSetTemplate{}, ""
+	}
+}
+
+// isTemplateOverride checks whether the given generator template override changes the name, the labels, the
+// annotations or the destination of the ApplicationSet's template.
+func isTemplateOverride(override argoprojv1alpha1.ApplicationSetTemplate) bool {
+	destination := override.Spec.Destination
+	return override.Name != "" || len(override.Labels) > 0 || len(override.Annotations) > 0 ||
+		destination.Server != "" || destination.Name != "" || destination.Namespace != ""
+}
+
+// hasTemplateOverride checks whether any generator of the given ApplicationSet overrides its template.
+func hasTemplateOverride(appSet *argoprojv1alpha1.ApplicationSet) bool {
+	for _, generator := range appSet.Spec.Generators {
+		if override, _ := generatorOverride(generator); isTemplateOverride(override) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// GenerateStaticApplications returns the Applications the given ApplicationSet generates whose name and destination
+// can be resolved at admission time. A template without placeholders yields a single Application, otherwise the
+// parameters of the List and Cluster generators are rendered into the template. A generator's template override is
+// applied on top of the template for the Applications of that generator only, and the template patch of the
+// ApplicationSet is applied to every Application. Applications produced by other generators are left for the
+// Application webhook to validate once they are generated.
+func GenerateStaticApplications(ctx context.Context, k8sClient client.Client,
+	appSet *argoprojv1alpha1.ApplicationSet) ([]GeneratedApplication, error) {
+	if !hasTemplateOverride(appSet) {
+		app, ok, err := renderGeneratedApplication(appSet, appSet.Spec.Template, map[string]string{})
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			return []GeneratedApplication{{Application: app, TemplateField: "spec.template"}}, nil
+		}
+	}
+
+	var applications []GeneratedApplication
+	for i, generator := range appSet.Spec.Generators {
+		override, overrideField := generatorOverride(generator)
+		templateField := "spec.template"
+		if isTemplateOverride(override) {
+			templateField = fmt.Sprintf("spec.generators[%d].%s", i, overrideField)
+		}
+
+		template := generatorTemplate(appSet, override)
+		app, ok, err := renderGeneratedApplication(appSet, template, map[string]string{})
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			applications = append(applications, GeneratedApplication{Application: app, TemplateField: templateField})
+			continue
+		}
+
+		var paramSets []map[string]string
+		switch {
+		case generator.List != nil:
+			paramSets, err = ListGeneratorParams(generator.List)
+		case generator.Clusters != nil:
+			paramSets, err = ClusterGeneratorParams(ctx, k8sClient, appSet.Namespace, generator.Clusters)
+		default:
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		for _, params := range paramSets {
+			app, ok, err := renderGeneratedApplication(appSet, template, params)
+			if err != nil {
+				return nil, err
+			}
+			if ok {
+				applications = append(applications, GeneratedApplication{Application: app, TemplateField: templateField})
+			}
+		}
+	}
+
+	return applications, nil
+}
@@ -0,0 +1,409 @@
+package utils
+
+import (
+	"context"
+	"maps"
+	"testing"
+
+	argoprojv1alpha1 "github.com/argoproj/argo-cd/v3/pkg/apis/application/v1alpha1"
+	rbacv1alpha1 "github.com/dana-team/application-rbac-validator/api/v1alpha1"
+	"github.com/dana-team/application-rbac-validator/internal/common"
+	testutils "github.com/dana-team/application-rbac-validator/test/utils"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestRenderTemplateString(t *testing.T) {
+	params := map[string]string{
+		"cluster":      sampleClusterName,
+		"values.owner": "team-a",
+	}
+
+	testCases := []struct {
+		name             string
+		value            string
+		expected         string
+		expectedResolved bool
+	}{
+		{
+			name:             "should keep a value without placeholders",
+			value:            sampleNamespaceName,
+			expected:         sampleNamespaceName,
+			expectedResolved: true,
+		},
+		{
+			name:             "should render fasttemplate placeholders",
+			value:            "{{cluster}}-{{ values.owner }}",
+			expected:         "my-cluster-team-a",
+			expectedResolved: true,
+		},
+		{
+			name:             "should render go template placeholders",
+			value:            "{{ .cluster }}-{{.values.owner}}",
+			expected:         "my-cluster-team-a",
+			expectedResolved: true,
+		},
+		{
+			name:             "should report unknown parameters as unresolved",
+			value:            "{{path.basename}}",
+			expected:         "{{path.basename}}",
+			expectedResolved: false,
+		},
+		{
+			name:             "should report unsupported template expressions as unresolved",
+			value:            "{{ .cluster | upper }}",
+			expected:         "{{ .cluster | upper }}",
+			expectedResolved: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			result, resolved := RenderTemplateString(tc.value, params)
+			if result != tc.expected {
+				t.Errorf("expected %q but got %q", tc.expected, result)
+			}
+			if resolved != tc.expectedResolved {
+				t.Errorf("expected resolved to be %v but got %v", tc.expectedResolved, resolved)
+			}
+		})
+	}
+}
+
+func TestRenderTemplateMap(t *testing.T) {
+	params := map[string]string{"cluster": sampleClusterName}
+	values := map[string]string{
+		"role":                   "bootstrap",
+		"argocd.dana.io/cluster": "{{cluster}}",
+		"owner":                  "{{values.owner}}",
+	}
+
+	rendered := renderTemplateMap(values, params)
+
+	expected := map[string]string{"role": "bootstrap", "argocd.dana.io/cluster": sampleClusterName}
+	if !maps.Equal(rendered, expected) {
+		t.Errorf("expected %v but got %v", expected, rendered)
+	}
+}
+
+func TestGenerateStaticApplications(t *testing.T) {
+	clusterSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "my-cluster-secret",
+			Namespace: sampleArgoCDNamespace,
+			Labels: map[string]string{
+				common.ArgoCDSecretTypeLabelKey: common.ArgoCDSecretTypeClusterValue,
+				"env":                           "prod",
+			},
+		},
+		Data: map[string][]byte{
+			"name":   []byte(sampleClusterName),
+			"server": []byte(sampleClusterServerURL),
+		},
+	}
+
+	testCases := []struct {
+		name                   string
+		template               argoprojv1alpha1.ApplicationSetTemplate
+		generators             []argoprojv1alpha1.ApplicationSetGenerator
+		templatePatch          string
+		existingObjs           []client.Object
+		expectedDestinations   []argoprojv1alpha1.ApplicationDestination
+		expectedTemplateFields []string
+		expectErr              bool
+	}{
+		{
+			name: "should return a single Application for a static template",
+			template: argoprojv1alpha1.ApplicationSetTemplate{
+				ApplicationSetTemplateMeta: argoprojv1alpha1.ApplicationSetTemplateMeta{Name: "static-app"},
+				Spec: argoprojv1alpha1.ApplicationSpec{
+					Destination: argoprojv1alpha1.ApplicationDestination{
+						Server:    sampleClusterServerURL,
+						Namespace: sampleNamespaceName,
+					},
+				},
+			},
+			expectedDestinations: []argoprojv1alpha1.ApplicationDestination{
+				{Server: sampleClusterServerURL, Namespace: sampleNamespaceName},
+			},
+		},
+		{
+			name: "should use the destination of a generator's template override over a static template",
+			template: argoprojv1alpha1.ApplicationSetTemplate{
+				ApplicationSetTemplateMeta: argoprojv1alpha1.ApplicationSetTemplateMeta{Name: "static-app"},
+			},
+			generators: []argoprojv1alpha1.ApplicationSetGenerator{
+				{
+					List: &argoprojv1alpha1.ListGenerator{
+						Elements: []apiextensionsv1.JSON{{Raw: []byte(`{"cluster":"a"}`)}},
+						Template: argoprojv1alpha1.ApplicationSetTemplate{
+							Spec: argoprojv1alpha1.ApplicationSpec{
+								Destination: argoprojv1alpha1.ApplicationDestination{
+									Server:    sampleClusterServerURL,
+									Namespace: sampleNamespaceName,
+								},
+							},
+						},
+					},
+				},
+			},
+			expectedDestinations: []argoprojv1alpha1.ApplicationDestination{
+				{Server: sampleClusterServerURL, Namespace: sampleNamespaceName},
+			},
+		},
+		{
+			name: "should keep a static template for the generators without a template override",
+			template: argoprojv1alpha1.ApplicationSetTemplate{
+				ApplicationSetTemplateMeta: argoprojv1alpha1.ApplicationSetTemplateMeta{Name: "static-app"},
+				Spec: argoprojv1alpha1.ApplicationSpec{
+					Destination: argoprojv1alpha1.ApplicationDestination{
+						Server:    sampleClusterServerURL,
+						Namespace: sampleNamespaceName,
+					},
+				},
+			},
+			generators: []argoprojv1alpha1.ApplicationSetGenerator{
+				{Git: &argoprojv1alpha1.GitGenerator{}},
+				{
+					List: &argoprojv1alpha1.ListGenerator{
+						Elements: []apiextensionsv1.JSON{{Raw: []byte(`{"cluster":"a"}`)}},
+						Template: argoprojv1alpha1.ApplicationSetTemplate{
+							Spec: argoprojv1alpha1.ApplicationSpec{
+								Destination: argoprojv1alpha1.ApplicationDestination{Server: sampleFQDNServerURL},
+							},
+						},
+					},
+				},
+			},
+			expectedDestinations: []argoprojv1alpha1.ApplicationDestination{
+				{Server: sampleClusterServerURL, Namespace: sampleNamespaceName},
+				{Server: sampleFQDNServerURL, Namespace: sampleNamespaceName},
+			},
+			expectedTemplateFields: []string{"spec.template", "spec.generators[1].list.template"},
+		},
+		{
+			name: "should apply the template patch to the generated Applications",
+			template: argoprojv1alpha1.ApplicationSetTemplate{
+				ApplicationSetTemplateMeta: argoprojv1alpha1.ApplicationSetTemplateMeta{Name: "{{ .cluster }}-app"},
+				Spec: argoprojv1alpha1.ApplicationSpec{
+					Destination: argoprojv1alpha1.ApplicationDestination{
+						Server:    "{{ .url }}",
+						Namespace: "default",
+					},
+				},
+			},
+			generators: []argoprojv1alpha1.ApplicationSetGenerator{
+				{
+					List: &argoprojv1alpha1.ListGenerator{
+						Elements: []apiextensionsv1.JSON{
+							{Raw: []byte(`{"cluster":"a","url":"https://api.a.example.com:6443","namespace":"ns-a"}`)},
+						},
+					},
+				},
+			},
+			templatePatch: "spec:\n  destination:\n    namespace: '{{ .namespace }}'\n",
+			expectedDestinations: []argoprojv1alpha1.ApplicationDestination{
+				{Server: "https://api.a.example.com:6443", Namespace: "ns-a"},
+			},
+			expectedTemplateFields: []string{"spec.template"},
+		},
+		{
+			name: "should skip Applications whose template patch cannot be resolved statically",
+			template: argoprojv1alpha1.ApplicationSetTemplate{
+				ApplicationSetTemplateMeta: argoprojv1alpha1.ApplicationSetTemplateMeta{Name: "static-app"},
+				Spec: argoprojv1alpha1.ApplicationSpec{
+					Destination: argoprojv1alpha1.ApplicationDestination{
+						Server:    sampleClusterServerURL,
+						Namespace: sampleNamespaceName,
+					},
+				},
+			},
+			templatePatch: "{{- if .autoSync }}\nspec:\n  syncPolicy:\n    automated: {}\n{{- end }}\n",
+		},
+		{
+			name: "should fail on a malformed template patch",
+			template: argoprojv1alpha1.ApplicationSetTemplate{
+				ApplicationSetTemplateMeta: argoprojv1alpha1.ApplicationSetTemplateMeta{Name: "static-app"},
+			},
+			templatePatch: "spec: [",
+			expectErr:     true,
+		},
+		{
+			name: "should render list generator elements",
+			template: argoprojv1alpha1.ApplicationSetTemplate{
+				ApplicationSetTemplateMeta: argoprojv1alpha1.ApplicationSetTemplateMeta{Name: "{{cluster}}-app"},
+				Spec: argoprojv1alpha1.ApplicationSpec{
+					Destination: argoprojv1alpha1.ApplicationDestination{
+						Server:    "{{url}}",
+						Namespace: "{{namespace}}",
+					},
+				},
+			},
+			generators: []argoprojv1alpha1.ApplicationSetGenerator{
+				{
+					List: &argoprojv1alpha1.ListGenerator{
+						Elements: []apiextensionsv1.JSON{
+							{Raw: []byte(`{"cluster":"a","url":"https://api.a.example.com:6443","namespace":"ns-a"}`)},
+							{Raw: []byte(`{"cluster":"b","url":"https://api.b.example.com:6443","namespace":"ns-b"}`)},
+						},
+					},
+				},
+			},
+			expectedDestinations: []argoprojv1alpha1.ApplicationDestination{
+				{Server: "https://api.a.example.com:6443", Namespace: "ns-a"},
+				{Server: "https://api.b.example.com:6443", Namespace: "ns-b"},
+			},
+		},
+		{
+			name: "should render cluster generator parameters for matching cluster secrets",
+			template: argoprojv1alpha1.ApplicationSetTemplate{
+				ApplicationSetTemplateMeta: argoprojv1alpha1.ApplicationSetTemplateMeta{Name: "{{name}}-app"},
+				Spec: argoprojv1alpha1.ApplicationSpec{
+					Destination: argoprojv1alpha1.ApplicationDestination{
+						Server:    "{{server}}",
+						Namespace: "{{values.namespace}}",
+					},
+				},
+			},
+			generators: []argoprojv1alpha1.ApplicationSetGenerator{
+				{
+					Clusters: &argoprojv1alpha1.ClusterGenerator{
+						Selector: metav1.LabelSelector{MatchLabels: map[string]string{"env": "prod"}},
+						Values:   map[string]string{"namespace": sampleNamespaceName},
+					},
+				},
+			},
+			existingObjs: []client.Object{clusterSecret},
+			expectedDestinations: []argoprojv1alpha1.ApplicationDestination{
+				{Server: sampleClusterServerURL, Namespace: sampleNamespaceName},
+			},
+		},
+		{
+			name: "should skip generators that cannot be resolved statically",
+			template: argoprojv1alpha1.ApplicationSetTemplate{
+				ApplicationSetTemplateMeta: argoprojv1alpha1.ApplicationSetTemplateMeta{Name: "{{path.basename}}"},
+				Spec: argoprojv1alpha1.ApplicationSpec{
+					Destination: argoprojv1alpha1.ApplicationDestination{
+						Server:    sampleClusterServerURL,
+						Namespace: "{{path.basename}}",
+					},
+				},
+			},
+			generators: []argoprojv1alpha1.ApplicationSetGenerator{
+				{Git: &argoprojv1alpha1.GitGenerator{}},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			appSet := &argoprojv1alpha1.ApplicationSet{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-appset", Namespace: sampleArgoCDNamespace},
+				Spec: argoprojv1alpha1.ApplicationSetSpec{
+					Generators: tc.generators,
+					Template:   tc.template,
+				},
+			}
+			if tc.templatePatch != "" {
+				appSet.Spec.TemplatePatch = &tc.templatePatch
+			}
+			cl := testutils.NewFakeClient(tc.existingObjs...)
+
+			result, err := GenerateStaticApplications(context.Background(), cl, appSet)
+			if tc.expectErr {
+				if err == nil {
+					t.Errorf("expected an error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if len(result) != len(tc.expectedDestinations) {
+				t.Fatalf("expected %d Applications but got %d", len(tc.expectedDestinations), len(result))
+			}
+			for i, destination := range tc.expectedDestinations {
+				if result[i].Spec.Destination != destination {
+					t.Errorf("expected destination %v at index %d but got %v", destination, i, result[i].Spec.Destination)
+				}
+				if result[i].Namespace != sampleArgoCDNamespace {
+					t.Errorf("expected Application namespace %q but got %q", sampleArgoCDNamespace, result[i].Namespace)
+				}
+			}
+			for i, field := range tc.expectedTemplateFields {
+				if result[i].TemplateField != field {
+					t.Errorf("expected template field %q at index %d but got %q", field, i, result[i].TemplateField)
+				}
+			}
+		})
+	}
+}
+
+func TestGenerateStaticApplicationsManagementMatching(t *testing.T) {
+	policy := newTestPolicy("policy", rbacv1alpha1.ApplicationRBACPolicySpec{
+		InstanceName: "team-a",
+		ManagementApplications: &rbacv1alpha1.ManagementApplications{
+			NamePatterns:        []string{"bootstrap-.+"},
+			MatchLabels:         map[string]string{"role": "bootstrap"},
+			MatchAnnotations:    map[string]string{"owner": "platform"},
+			AllowedDestinations: []rbacv1alpha1.ManagementDestination{{Cluster: "in-cluster"}},
+		},
+	})
+	appSet := &argoprojv1alpha1.ApplicationSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "bootstrap", Namespace: samplePolicyNamespace},
+		Spec: argoprojv1alpha1.ApplicationSetSpec{
+			Template: argoprojv1alpha1.ApplicationSetTemplate{
+				ApplicationSetTemplateMeta: argoprojv1alpha1.ApplicationSetTemplateMeta{
+					Name:        "bootstrap-{{cluster}}",
+					Labels:      map[string]string{"role": "app"},
+					Annotations: map[string]string{"owner": "{{owner}}"},
+				},
+				Spec: argoprojv1alpha1.ApplicationSpec{
+					Destination: argoprojv1alpha1.ApplicationDestination{
+						Server:    common.InClusterServerUrl,
+						Namespace: "{{cluster}}",
+					},
+				},
+			},
+			Generators: []argoprojv1alpha1.ApplicationSetGenerator{
+				{
+					List: &argoprojv1alpha1.ListGenerator{
+						Elements: []apiextensionsv1.JSON{{Raw: []byte(`{"cluster":"a","owner":"platform"}`)}},
+						Template: argoprojv1alpha1.ApplicationSetTemplate{
+							ApplicationSetTemplateMeta: argoprojv1alpha1.ApplicationSetTemplateMeta{
+								Labels: map[string]string{"role": "bootstrap"},
+							},
+						},
+					},
+				},
+				{
+					List: &argoprojv1alpha1.ListGenerator{
+						Elements: []apiextensionsv1.JSON{{Raw: []byte(`{"cluster":"b","owner":"platform"}`)}},
+					},
+				},
+			},
+		},
+	}
+
+	applications, err := GenerateStaticApplications(context.Background(), testutils.NewFakeClient(), appSet)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	matcher, err := NewManagementMatcher(policy, policy.Spec.InstanceName)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := map[string]bool{"bootstrap-a": true, "bootstrap-b": false}
+	if len(applications) != len(expected) {
+		t.Fatalf("expected %d Applications but got %d", len(expected), len(applications))
+	}
+	for _, application := range applications {
+		if matches := matcher.Matches(application.Application); matches != expected[application.Name] {
+			t.Errorf("expected Application %s to match %v but got %v", application.Name, expected[application.Name], matches)
+		}
+	}
+}
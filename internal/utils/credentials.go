@@ -0,0 +1,246 @@
+package utils
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	argoprojv1alpha1 "github.com/argoproj/argo-cd/v3/pkg/apis/application/v1alpha1"
+	"github.com/dana-team/application-rbac-validator/internal/common"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ClusterCredentials holds what is needed to securely access a destination cluster.
+type ClusterCredentials struct {
+	// Token is the bearer token used to authenticate against the destination cluster.
+	Token string
+	// CertData and KeyData are the PEM encoded client certificate and key used to authenticate against the
+	// destination cluster.
+	CertData []byte
+	KeyData  []byte
+	// CAData is the PEM encoded CA bundle used to verify the destination cluster's serving certificate. The system
+	// trust store is used when it is empty.
+	CAData []byte
+	// Insecure skips the verification of the destination cluster's serving certificate. It is only set when the
+	// cluster is explicitly opted in, either in the cluster tokens ConfigMap or in its Argo CD cluster config.
+	Insecure bool
+	// ServiceAccount is the validator ServiceAccount on the destination cluster. When set, the token above is only a
+	// bootstrap credential used to mint short-lived tokens for this ServiceAccount through the TokenRequest API.
+	ServiceAccount types.NamespacedName
+}
+
+// FetchClusterCredentials fetches the credentials of the destination cluster. When clusterSecretsNamespace is set
+// and holds an Argo CD cluster secret for the server with a bearer token or client certificate, its credentials are
+// used. Otherwise the credentials Secret of the server inside the webhook's namespace is used, falling back to the
+// cluster tokens ConfigMap for installations that were not migrated yet. When neither holds a CA bundle, the caData
+// of the matching Argo CD cluster secret inside clusterSecretsNamespace, or the webhook's namespace when it is not
+// set, is used. Cluster secrets inside tenant namespaces are never trusted.
+func FetchClusterCredentials(ctx context.Context, k8sClient client.Client, clusterSecretsNamespace, currentNamespace, serverURL string) (ClusterCredentials, error) {
+	if clusterSecretsNamespace != "" {
+		credentials, found, err := FetchArgoClusterCredentials(ctx, k8sClient, clusterSecretsNamespace, serverURL)
+		if err != nil {
+			return ClusterCredentials{}, err
+		}
+		if found {
+			return credentials, nil
+		}
+	}
+
+	credentials, found, err := FetchClusterCredentialsSecret(ctx, k8sClient, currentNamespace, serverURL)
+	if err != nil {
+		return ClusterCredentials{}, err
+	}
+	if !found {
+		credentials, err = fetchClusterCredentialsConfigMap(ctx, k8sClient, currentNamespace, serverURL)
+		if err != nil {
+			return ClusterCredentials{}, err
+		}
+	}
+
+	if len(credentials.CAData) == 0 && !credentials.Insecure {
+		caNamespace := currentNamespace
+		if clusterSecretsNamespace != "" {
+			caNamespace = clusterSecretsNamespace
+		}
+
+		clusterConfig, err := FetchArgoClusterConfig(ctx, k8sClient, caNamespace, serverURL)
+		if err != nil {
+			return ClusterCredentials{}, err
+		}
+		if clusterConfig != nil {
+			credentials.CAData = clusterConfig.TLSClientConfig.CAData
+		}
+	}
+
+	return credentials, nil
+}
+
+// ServerURLHash returns the label value identifying the credentials Secret of the given server. Unlike
+// FormatFileSafeServerURL, it is a hash of the whole URL, so distinct servers never share a value.
+func ServerURLHash(serverURL string) string {
+	sum := sha256.Sum256([]byte(strings.TrimSuffix(serverURL, "/")))
+	return hex.EncodeToString(sum[:])[:common.LabelValueMaxLength]
+}
+
+// FetchClusterCredentialsSecret returns the credentials stored in the credentials Secret of the given server inside
+// the given namespace. The Secret is found by the hash of the server URL and its server key must match the server.
+// It returns false if there is no such Secret.
+func FetchClusterCredentialsSecret(ctx context.Context, k8sClient client.Client, namespace, serverURL string) (ClusterCredentials, bool, error) {
+	secretList := &corev1.SecretList{}
+	labelSelector := client.MatchingLabels{
+		common.ClusterCredentialsLabelKey: common.LabelValueTrue,
+		common.ClusterServerHashLabelKey:  ServerURLHash(serverURL),
+	}
+	if err := k8sClient.List(ctx, secretList, client.InNamespace(namespace), labelSelector); err != nil {
+		return ClusterCredentials{}, false, fmt.Errorf("failed to list cluster credentials secrets in namespace %s: %w", namespace, err)
+	}
+
+	for _, secret := range secretList.Items {
+		if strings.TrimSuffix(string(secret.Data[common.ClusterCredentialsServerKey]), "/") != strings.TrimSuffix(serverURL, "/") {
+			continue
+		}
+
+		credentials, err := ClusterCredentialsFromSecret(&secret)
+		if err != nil {
+			return ClusterCredentials{}, false, err
+		}
+		return credentials, true, nil
+	}
+
+	return ClusterCredentials{}, false, nil
+}
+
+// ClusterCredentialsFromSecret returns the credentials stored in the given credentials Secret.
+func ClusterCredentialsFromSecret(secret *corev1.Secret) (ClusterCredentials, error) {
+	token, ok := secret.Data[common.ClusterCredentialsTokenKey]
+	if !ok {
+		return ClusterCredentials{}, fmt.Errorf("key %q not found in Secret %q", common.ClusterCredentialsTokenKey, secret.Name)
+	}
+
+	credentials := ClusterCredentials{
+		Token:    strings.TrimSpace(string(token)),
+		CAData:   secret.Data[common.ClusterCredentialsCAKey],
+		Insecure: string(secret.Data[common.ClusterCredentialsInsecureKey]) == common.LabelValueTrue,
+	}
+
+	if serviceAccount, ok := secret.Data[common.ClusterServiceAccountKey]; ok {
+		namespace, name, found := strings.Cut(strings.TrimSpace(string(serviceAccount)), "/")
+		if !found || namespace == "" || name == "" {
+			return ClusterCredentials{}, fmt.Errorf("key %q of Secret %q must be formatted as <namespace>/<name>",
+				common.ClusterServiceAccountKey, secret.Name)
+		}
+		credentials.ServiceAccount = types.NamespacedName{Namespace: namespace, Name: name}
+	}
+
+	return credentials, nil
+}
+
+// fetchClusterCredentialsConfigMap reads the token, the CA bundle and the insecure opt-in of the given server from
+// the cluster tokens ConfigMap inside the given namespace.
+func fetchClusterCredentialsConfigMap(ctx context.Context, k8sClient client.Client, namespace, serverURL string) (ClusterCredentials, error) {
+	token, err := FetchClusterToken(ctx, k8sClient, namespace, serverURL)
+	if err != nil {
+		return ClusterCredentials{}, err
+	}
+
+	credentials := ClusterCredentials{Token: token}
+
+	fileSafeServerURL := FormatFileSafeServerURL(serverURL)
+	caData, err := fetchOptionalConfigMapValue(ctx, k8sClient, namespace, common.ClusterTokensConfigMapName,
+		fileSafeServerURL+common.ClusterCAConfigMapKeySuffix)
+	if err != nil {
+		return ClusterCredentials{}, err
+	}
+	credentials.CAData = []byte(caData)
+
+	insecure, err := fetchOptionalConfigMapValue(ctx, k8sClient, namespace, common.ClusterTokensConfigMapName,
+		fileSafeServerURL+common.ClusterInsecureConfigMapKeySuffix)
+	if err != nil {
+		return ClusterCredentials{}, err
+	}
+	credentials.Insecure = insecure == common.LabelValueTrue
+
+	return credentials, nil
+}
+
+// FetchArgoClusterCredentials returns the credentials stored in the Argo CD cluster secret of the given server inside
+// the given namespace. It returns false if there is no such secret, or if it holds neither a bearer token nor a
+// client certificate, e.g. because it relies on an exec or cloud provider plugin.
+func FetchArgoClusterCredentials(ctx context.Context, k8sClient client.Client, namespace, serverURL string) (ClusterCredentials, bool, error) {
+	clusterConfig, err := FetchArgoClusterConfig(ctx, k8sClient, namespace, serverURL)
+	if err != nil {
+		return ClusterCredentials{}, false, err
+	}
+	if clusterConfig == nil {
+		return ClusterCredentials{}, false, nil
+	}
+
+	tlsConfig := clusterConfig.TLSClientConfig
+	if clusterConfig.BearerToken == "" && (len(tlsConfig.CertData) == 0 || len(tlsConfig.KeyData) == 0) {
+		return ClusterCredentials{}, false, nil
+	}
+
+	return ClusterCredentials{
+		Token:    clusterConfig.BearerToken,
+		CertData: tlsConfig.CertData,
+		KeyData:  tlsConfig.KeyData,
+		CAData:   tlsConfig.CAData,
+		Insecure: tlsConfig.Insecure,
+	}, true, nil
+}
+
+// FetchArgoClusterConfig returns the connection config of the Argo CD cluster secret of the given server inside the
+// given namespace, or nil if there is no such secret.
+func FetchArgoClusterConfig(ctx context.Context, k8sClient client.Client, namespace, serverURL string) (*argoprojv1alpha1.ClusterConfig, error) {
+	secretList := &corev1.SecretList{}
+	labelSelector := client.MatchingLabels{
+		common.ArgoCDSecretTypeLabelKey: common.ArgoCDSecretTypeClusterValue,
+	}
+	if err := k8sClient.List(ctx, secretList, client.InNamespace(namespace), labelSelector); err != nil {
+		return nil, fmt.Errorf("failed to list cluster secrets in namespace %s: %w", namespace, err)
+	}
+
+	for _, secret := range secretList.Items {
+		if strings.TrimSuffix(string(secret.Data["server"]), "/") != strings.TrimSuffix(serverURL, "/") {
+			continue
+		}
+
+		clusterConfig := &argoprojv1alpha1.ClusterConfig{}
+		if rawConfig, ok := secret.Data["config"]; ok {
+			if err := json.Unmarshal(rawConfig, clusterConfig); err != nil {
+				return nil, fmt.Errorf("failed to parse config of cluster secret %q: %w", secret.Name, err)
+			}
+		}
+		return clusterConfig, nil
+	}
+
+	return nil, nil
+}
+
+// WrapClusterConnectionError returns a descriptive error when the given error was caused by a failed TLS
+// verification of the destination cluster, and the given error otherwise.
+func WrapClusterConnectionError(cluster string, err error) error {
+	var (
+		unknownAuthorityError x509.UnknownAuthorityError
+		certificateError      x509.CertificateInvalidError
+		hostnameError         x509.HostnameError
+		recordHeaderError     tls.RecordHeaderError
+		verificationError     *tls.CertificateVerificationError
+	)
+
+	if errors.As(err, &unknownAuthorityError) || errors.As(err, &certificateError) || errors.As(err, &hostnameError) ||
+		errors.As(err, &recordHeaderError) || errors.As(err, &verificationError) {
+		return fmt.Errorf("failed to verify the TLS certificate of destination cluster %s, configure its CA bundle "+
+			"in the %s ConfigMap or its Argo CD cluster secret: %w", cluster, common.ClusterTokensConfigMapName, err)
+	}
+
+	return err
+}
@@ -0,0 +1,375 @@
+package utils
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/dana-team/application-rbac-validator/internal/common"
+	testutils "github.com/dana-team/application-rbac-validator/test/utils"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	sampleToken                  = "sample-token"
+	sampleCAData                 = "-----BEGIN CERTIFICATE-----\nconfigmap\n-----END CERTIFICATE-----\n"
+	sampleArgoCAData             = "-----BEGIN CERTIFICATE-----\nargocd\n-----END CERTIFICATE-----\n"
+	sampleArgoCAConfig           = `{"bearerToken":"argocd-token","tlsClientConfig":{"caData":"LS0tLS1CRUdJTiBDRVJUSUZJQ0FURS0tLS0tCmFyZ29jZAotLS0tLUVORCBDRVJUSUZJQ0FURS0tLS0tCg=="}}`
+	sampleArgoExecConfig         = `{"execProviderConfig":{"command":"argocd-k8s-auth"}}`
+	sampleArgoToken              = "argocd-token"
+	sampleArgoCDSecretsNamespace = "argocd-system"
+)
+
+func TestFetchClusterCredentials(t *testing.T) {
+	fileSafeServerURL := FormatFileSafeServerURL(sampleClusterServerURL)
+
+	tokensConfigMap := func(data map[string]string) *corev1.ConfigMap {
+		data[fileSafeServerURL+common.ClusterTokenConfigMapKeySuffix] = sampleToken
+		return &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      common.ClusterTokensConfigMapName,
+				Namespace: sampleNamespaceObjectName,
+			},
+			Data: data,
+		}
+	}
+	credentialsSecret := func(labeledServer, server string) *corev1.Secret {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "cluster-credentials",
+				Namespace: sampleNamespaceObjectName,
+				Labels: map[string]string{
+					common.ClusterCredentialsLabelKey: common.LabelValueTrue,
+					common.ClusterServerHashLabelKey:  ServerURLHash(labeledServer),
+				},
+			},
+			Data: map[string][]byte{
+				common.ClusterCredentialsServerKey: []byte(server),
+				common.ClusterCredentialsTokenKey:  []byte(sampleToken),
+				common.ClusterCredentialsCAKey:     []byte(sampleCAData),
+			},
+		}
+	}
+	argoClusterSecret := func(namespace string) *corev1.Secret {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "my-cluster-secret",
+				Namespace: namespace,
+				Labels: map[string]string{
+					common.ArgoCDSecretTypeLabelKey: common.ArgoCDSecretTypeClusterValue,
+				},
+			},
+			Data: map[string][]byte{
+				"server": []byte(sampleClusterServerURL),
+				"config": []byte(sampleArgoCAConfig),
+			},
+		}
+	}
+
+	testCases := []struct {
+		name             string
+		existingObjs     []client.Object
+		expectedCAData   string
+		expectedInsecure bool
+		expectError      bool
+	}{
+		{
+			name:         "should verify against the system trust store without a CA bundle",
+			existingObjs: []client.Object{tokensConfigMap(map[string]string{})},
+		},
+		{
+			name: "should read the CA bundle next to the token",
+			existingObjs: []client.Object{
+				tokensConfigMap(map[string]string{fileSafeServerURL + common.ClusterCAConfigMapKeySuffix: sampleCAData}),
+				argoClusterSecret(sampleNamespaceObjectName),
+			},
+			expectedCAData: strings.TrimSpace(sampleCAData),
+		},
+		{
+			name:           "should fall back to the CA bundle of the Argo CD cluster secret",
+			existingObjs:   []client.Object{tokensConfigMap(map[string]string{}), argoClusterSecret(sampleNamespaceObjectName)},
+			expectedCAData: sampleArgoCAData,
+		},
+		{
+			name:         "should not trust the CA bundle of a cluster secret in the Application's namespace",
+			existingObjs: []client.Object{tokensConfigMap(map[string]string{}), argoClusterSecret(sampleNamespaceName)},
+		},
+		{
+			name: "should skip verification only when explicitly opted in",
+			existingObjs: []client.Object{
+				tokensConfigMap(map[string]string{fileSafeServerURL + common.ClusterInsecureConfigMapKeySuffix: common.LabelValueTrue}),
+				argoClusterSecret(sampleNamespaceObjectName),
+			},
+			expectedInsecure: true,
+		},
+		{
+			name: "should prefer the credentials Secret over the ConfigMap",
+			existingObjs: []client.Object{
+				tokensConfigMap(map[string]string{fileSafeServerURL + common.ClusterInsecureConfigMapKeySuffix: common.LabelValueTrue}),
+				credentialsSecret(sampleClusterServerURL, sampleClusterServerURL),
+			},
+			expectedCAData: sampleCAData,
+		},
+		{
+			name: "should ignore a credentials Secret of another server",
+			existingObjs: []client.Object{
+				tokensConfigMap(map[string]string{}),
+				credentialsSecret(sampleClusterServerURL, "https://api.my.cluster.example.com:6443"),
+			},
+		},
+		{
+			name:           "should read the credentials Secret without the ConfigMap",
+			existingObjs:   []client.Object{credentialsSecret(sampleClusterServerURL, sampleClusterServerURL)},
+			expectedCAData: sampleCAData,
+		},
+		{
+			name:        "should return error when the token is missing",
+			expectError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cl := testutils.NewFakeClient(tc.existingObjs...)
+
+			result, err := FetchClusterCredentials(context.Background(), cl, "", sampleNamespaceObjectName, sampleClusterServerURL)
+			if tc.expectError && err == nil {
+				t.Errorf("expected error but got none")
+			}
+			if !tc.expectError && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if tc.expectError {
+				return
+			}
+			if result.Token != sampleToken {
+				t.Errorf("expected token %q but got %q", sampleToken, result.Token)
+			}
+			if string(result.CAData) != tc.expectedCAData {
+				t.Errorf("expected CA data %q but got %q", tc.expectedCAData, string(result.CAData))
+			}
+			if result.Insecure != tc.expectedInsecure {
+				t.Errorf("expected insecure to be %v but got %v", tc.expectedInsecure, result.Insecure)
+			}
+		})
+	}
+}
+
+func TestFetchClusterCredentialsFromArgoCDSecrets(t *testing.T) {
+	tokensConfigMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      common.ClusterTokensConfigMapName,
+			Namespace: sampleNamespaceObjectName,
+		},
+		Data: map[string]string{
+			FormatFileSafeServerURL(sampleClusterServerURL) + common.ClusterTokenConfigMapKeySuffix: sampleToken,
+		},
+	}
+	argoClusterSecret := func(config string) *corev1.Secret {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "my-cluster-secret",
+				Namespace: sampleArgoCDSecretsNamespace,
+				Labels: map[string]string{
+					common.ArgoCDSecretTypeLabelKey: common.ArgoCDSecretTypeClusterValue,
+				},
+			},
+			Data: map[string][]byte{
+				"server": []byte(sampleClusterServerURL),
+				"config": []byte(config),
+			},
+		}
+	}
+
+	testCases := []struct {
+		name           string
+		existingObjs   []client.Object
+		expectedToken  string
+		expectedCAData string
+	}{
+		{
+			name:           "should use the credentials of the Argo CD cluster secret",
+			existingObjs:   []client.Object{tokensConfigMap, argoClusterSecret(sampleArgoCAConfig)},
+			expectedToken:  sampleArgoToken,
+			expectedCAData: sampleArgoCAData,
+		},
+		{
+			name:          "should fall back to the cluster tokens ConfigMap without a cluster secret",
+			existingObjs:  []client.Object{tokensConfigMap},
+			expectedToken: sampleToken,
+		},
+		{
+			name:          "should fall back to the cluster tokens ConfigMap when the cluster secret uses an exec provider",
+			existingObjs:  []client.Object{tokensConfigMap, argoClusterSecret(sampleArgoExecConfig)},
+			expectedToken: sampleToken,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cl := testutils.NewFakeClient(tc.existingObjs...)
+
+			result, err := FetchClusterCredentials(context.Background(), cl, sampleArgoCDSecretsNamespace,
+				sampleNamespaceObjectName, sampleClusterServerURL)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result.Token != tc.expectedToken {
+				t.Errorf("expected token %q but got %q", tc.expectedToken, result.Token)
+			}
+			if string(result.CAData) != tc.expectedCAData {
+				t.Errorf("expected CA data %q but got %q", tc.expectedCAData, string(result.CAData))
+			}
+		})
+	}
+}
+
+func TestClusterCredentialsFromSecret(t *testing.T) {
+	testCases := []struct {
+		name                   string
+		data                   map[string]string
+		expectedServiceAccount types.NamespacedName
+		expectError            bool
+	}{
+		{
+			name: "should read a token without a ServiceAccount",
+			data: map[string]string{common.ClusterCredentialsTokenKey: sampleToken},
+		},
+		{
+			name: "should read the ServiceAccount tokens are minted for",
+			data: map[string]string{
+				common.ClusterCredentialsTokenKey: sampleToken,
+				common.ClusterServiceAccountKey:   "application-rbac-validator/validator",
+			},
+			expectedServiceAccount: types.NamespacedName{Namespace: "application-rbac-validator", Name: "validator"},
+		},
+		{
+			name: "should return error for a ServiceAccount without a namespace",
+			data: map[string]string{
+				common.ClusterCredentialsTokenKey: sampleToken,
+				common.ClusterServiceAccountKey:   "validator",
+			},
+			expectError: true,
+		},
+		{
+			name:        "should return error when the token is missing",
+			data:        map[string]string{},
+			expectError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "cluster-credentials"}, Data: map[string][]byte{}}
+			for key, value := range tc.data {
+				secret.Data[key] = []byte(value)
+			}
+
+			credentials, err := ClusterCredentialsFromSecret(secret)
+			if tc.expectError {
+				if err == nil {
+					t.Errorf("expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if credentials.Token != sampleToken {
+				t.Errorf("expected token %q but got %q", sampleToken, credentials.Token)
+			}
+			if credentials.ServiceAccount != tc.expectedServiceAccount {
+				t.Errorf("expected ServiceAccount %v but got %v", tc.expectedServiceAccount, credentials.ServiceAccount)
+			}
+		})
+	}
+}
+
+func TestServerURLHash(t *testing.T) {
+	testCases := []struct {
+		name          string
+		serverURL     string
+		otherURL      string
+		expectedEqual bool
+	}{
+		{
+			name:      "should distinguish servers mapped to the same file safe name",
+			serverURL: "https://api.my.cluster.example.com:6443",
+			otherURL:  "https://api.my-cluster.example.com:6443",
+		},
+		{
+			name:          "should ignore a trailing slash",
+			serverURL:     sampleClusterServerURL,
+			otherURL:      sampleClusterServerURL + "/",
+			expectedEqual: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			hash := ServerURLHash(tc.serverURL)
+			if len(hash) != common.LabelValueMaxLength {
+				t.Errorf("expected a hash of length %d but got %d", common.LabelValueMaxLength, len(hash))
+			}
+			if (hash == ServerURLHash(tc.otherURL)) != tc.expectedEqual {
+				t.Errorf("expected hashes of %q and %q to be equal: %v", tc.serverURL, tc.otherURL, tc.expectedEqual)
+			}
+		})
+	}
+}
+
+func TestBuildClusterRestConfig(t *testing.T) {
+	config := BuildClusterRestConfig(sampleClusterServerURL, ClusterCredentials{Token: sampleToken, CAData: []byte(sampleCAData)})
+	if config.Insecure {
+		t.Errorf("expected TLS verification to be enabled")
+	}
+	if string(config.CAData) != sampleCAData {
+		t.Errorf("expected CA data %q but got %q", sampleCAData, string(config.CAData))
+	}
+
+	config = BuildClusterRestConfig(sampleClusterServerURL, ClusterCredentials{Token: sampleToken, CAData: []byte(sampleCAData), Insecure: true})
+	if !config.Insecure || len(config.CAData) != 0 {
+		t.Errorf("expected TLS verification to be skipped without a CA bundle")
+	}
+}
+
+func TestWrapClusterConnectionError(t *testing.T) {
+	testCases := []struct {
+		name             string
+		err              error
+		expectTLSMessage bool
+	}{
+		{
+			name:             "should describe unknown certificate authorities",
+			err:              fmt.Errorf("SubjectAccessReview failed: %w", x509.UnknownAuthorityError{}),
+			expectTLSMessage: true,
+		},
+		{
+			name:             "should describe hostname mismatches",
+			err:              fmt.Errorf("SubjectAccessReview failed: %w", x509.HostnameError{Certificate: &x509.Certificate{}, Host: "api"}),
+			expectTLSMessage: true,
+		},
+		{
+			name: "should keep other errors",
+			err:  errors.New("connection refused"),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			result := WrapClusterConnectionError(sampleClusterServerURL, tc.err)
+			if !errors.Is(result, tc.err) {
+				t.Errorf("expected the original error to be wrapped but got %v", result)
+			}
+			if strings.Contains(result.Error(), "TLS certificate") != tc.expectTLSMessage {
+				t.Errorf("unexpected error message: %v", result)
+			}
+		})
+	}
+}
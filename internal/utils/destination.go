@@ -0,0 +1,61 @@
+package utils
+
+import (
+	"context"
+	"net"
+	"net/url"
+	"strings"
+
+	argoprojv1alpha1 "github.com/argoproj/argo-cd/v3/pkg/apis/application/v1alpha1"
+	"github.com/dana-team/application-rbac-validator/internal/common"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// DestinationKey returns the canonical key of the cluster of the given destination server, so that destinations
+// written in different forms can be compared:
+//  1. An in-cluster alias (e.g. "in-cluster", "https://kubernetes.default.svc") is "in-cluster".
+//  2. A server URL (e.g. "https://api.my-cluster.example.com:6443/") is its lowercase host without the "api."
+//     prefix and the port (e.g. "my-cluster.example.com"), which is also the prefix of its cluster secret name.
+//  3. A short cluster name (e.g. "my-cluster") is the key of the server URL built from it with the given domain
+//     (e.g. "my-cluster.example.com"), or the lowercase name when no domain is given.
+func DestinationKey(destServer, domain string) string {
+	if IsInCluster(destServer) {
+		return common.InClusterValues[0]
+	}
+
+	key := strings.TrimPrefix(serverHost(destServer), "api.")
+	if domain != "" && key != "" && IsClusterNameKey(key) {
+		return strings.TrimPrefix(serverHost(BuildServerUrl(key, domain)), "api.")
+	}
+
+	return key
+}
+
+// ResolveDestinationKey resolves the destination server of the Application and returns its destination key, resolving
+// a short cluster name with the given domain.
+func ResolveDestinationKey(ctx context.Context, c client.Client, app *argoprojv1alpha1.Application, domain string) (string, error) {
+	destServer, err := ResolveDestinationServer(ctx, c, app)
+	if err != nil {
+		return "", err
+	}
+
+	return DestinationKey(destServer, domain), nil
+}
+
+// IsClusterNameKey checks whether the given destination key is a short cluster name rather than a host.
+func IsClusterNameKey(destinationKey string) bool {
+	return destinationKey != common.InClusterValues[0] && !strings.Contains(destinationKey, ".")
+}
+
+// serverHost returns the lowercase host of the given server URL without its port, or the given value itself if it
+// is not a URL.
+func serverHost(server string) string {
+	host := strings.TrimSuffix(strings.ToLower(strings.TrimSpace(server)), "/")
+	if parsedUrl, err := url.Parse(host); err == nil && parsedUrl.Host != "" {
+		host = parsedUrl.Hostname()
+	} else if hostname, _, err := net.SplitHostPort(host); err == nil {
+		host = hostname
+	}
+
+	return strings.TrimSuffix(host, ".")
+}
@@ -0,0 +1,308 @@
+package utils
+
+import (
+	"context"
+	"testing"
+
+	argoprojv1alpha1 "github.com/argoproj/argo-cd/v3/pkg/apis/application/v1alpha1"
+	"github.com/dana-team/application-rbac-validator/internal/common"
+	testutils "github.com/dana-team/application-rbac-validator/test/utils"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestDestinationKey(t *testing.T) {
+	testCases := []struct {
+		name     string
+		server   string
+		domain   string
+		expected string
+	}{
+		{
+			name:     "should strip the api prefix and the port of a server URL",
+			server:   sampleClusterServerURL,
+			expected: "my-cluster.example.com",
+		},
+		{
+			name:     "should ignore the case and the trailing slash of a server URL",
+			server:   "HTTPS://API.My-Cluster.Example.com:6443/",
+			expected: "my-cluster.example.com",
+		},
+		{
+			name:     "should keep a short cluster name without a domain",
+			server:   sampleClusterName,
+			expected: sampleClusterName,
+		},
+		{
+			name:     "should resolve a short cluster name with the domain",
+			server:   sampleClusterName,
+			domain:   sampleServerUrlDomain,
+			expected: "my-cluster.example.com",
+		},
+		{
+			name:     "should not apply the domain to a server URL",
+			server:   sampleFQDNServerURL,
+			domain:   sampleServerUrlDomain,
+			expected: "my-cluster.domain.example.com",
+		},
+		{
+			name:     "should map the in-cluster server URL to in-cluster",
+			server:   "https://kubernetes.default.svc",
+			domain:   sampleServerUrlDomain,
+			expected: inClusterAlias,
+		},
+		{
+			name:     "should map the in-cluster service name with a port to in-cluster",
+			server:   "kubernetes.default.svc.cluster.local:443",
+			expected: inClusterAlias,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			result := DestinationKey(tc.server, tc.domain)
+			if result != tc.expected {
+				t.Errorf("expected %v but got %v", tc.expected, result)
+			}
+		})
+	}
+}
+
+func TestResolveDestinationKey(t *testing.T) {
+	testCases := []struct {
+		name     string
+		server   string
+		other    string
+		domain   string
+		expected bool
+	}{
+		{
+			name:     "should match a server URL and the same URL written differently",
+			server:   sampleClusterServerURL,
+			other:    "https://api.my-cluster.example.com:6443/",
+			expected: true,
+		},
+		{
+			name:     "should match a short cluster name and its server URL in the domain",
+			server:   sampleClusterName,
+			other:    sampleClusterServerURL,
+			domain:   sampleServerUrlDomain,
+			expected: true,
+		},
+		{
+			name:     "should not match a short cluster name and a server URL in another domain",
+			server:   sampleClusterName,
+			other:    sampleFQDNServerURL,
+			domain:   sampleServerUrlDomain,
+			expected: false,
+		},
+		{
+			name:     "should not match a short cluster name and a server URL without a domain",
+			server:   sampleClusterName,
+			other:    sampleClusterServerURL,
+			expected: false,
+		},
+		{
+			name:     "should match the in-cluster aliases",
+			server:   inClusterAlias,
+			other:    "https://kubernetes.default.svc",
+			expected: true,
+		},
+		{
+			name:     "should not match clusters with the same name in different domains",
+			server:   sampleClusterServerURL,
+			other:    sampleFQDNServerURL,
+			expected: false,
+		},
+	}
+
+	newApp := func(server string) *argoprojv1alpha1.Application {
+		return &argoprojv1alpha1.Application{
+			ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: sampleArgoCDNamespace},
+			Spec: argoprojv1alpha1.ApplicationSpec{
+				Destination: argoprojv1alpha1.ApplicationDestination{Server: server, Namespace: sampleNamespaceName},
+			},
+		}
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			k8sClient := testutils.NewFakeClient()
+			key, err := ResolveDestinationKey(context.Background(), k8sClient, newApp(tc.server), tc.domain)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			otherKey, err := ResolveDestinationKey(context.Background(), k8sClient, newApp(tc.other), tc.domain)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result := key == otherKey; result != tc.expected {
+				t.Errorf("expected %v but got %v", tc.expected, result)
+			}
+		})
+	}
+}
+
+func TestFetchClusterSecret(t *testing.T) {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-cluster.example.com-cluster-secret", Namespace: sampleArgoCDNamespace},
+	}
+	fqdnSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-cluster.domain.example.com-cluster-secret", Namespace: sampleArgoCDNamespace},
+	}
+
+	testCases := []struct {
+		name           string
+		server         string
+		domain         string
+		secrets        []*corev1.Secret
+		expectedSecret string
+		expectNotFound bool
+	}{
+		{
+			name:           "should fetch the secret of a server URL",
+			server:         sampleClusterServerURL,
+			secrets:        []*corev1.Secret{secret, fqdnSecret},
+			expectedSecret: secret.Name,
+		},
+		{
+			name:           "should fetch the secret of a short cluster name resolved with the domain",
+			server:         sampleClusterName,
+			domain:         sampleServerUrlDomain,
+			secrets:        []*corev1.Secret{secret, fqdnSecret},
+			expectedSecret: secret.Name,
+		},
+		{
+			name:           "should fetch the only secret of a short cluster name without a domain",
+			server:         sampleClusterName,
+			secrets:        []*corev1.Secret{fqdnSecret},
+			expectedSecret: fqdnSecret.Name,
+		},
+		{
+			name:    "should fail on a short cluster name matching several secrets without a domain",
+			server:  sampleClusterName,
+			secrets: []*corev1.Secret{secret, fqdnSecret},
+		},
+		{
+			name:           "should not find the secret of a short cluster name outside the domain",
+			server:         sampleClusterName,
+			domain:         sampleServerUrlDomain,
+			secrets:        []*corev1.Secret{fqdnSecret},
+			expectNotFound: true,
+		},
+		{
+			name:           "should not find the secret of another cluster name",
+			server:         "other-cluster",
+			secrets:        []*corev1.Secret{secret, fqdnSecret},
+			expectNotFound: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var objects []client.Object
+			for _, secret := range tc.secrets {
+				objects = append(objects, secret)
+			}
+			k8sClient := testutils.NewFakeClient(objects...)
+
+			result, err := FetchClusterSecret(context.Background(), k8sClient, sampleArgoCDNamespace, tc.server, tc.domain)
+			if tc.expectNotFound {
+				if !errors.IsNotFound(err) {
+					t.Errorf("expected a not found error but got %v", err)
+				}
+				return
+			}
+			if tc.expectedSecret == "" {
+				if err == nil || errors.IsNotFound(err) {
+					t.Errorf("expected an ambiguous cluster name error but got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result.Name != tc.expectedSecret {
+				t.Errorf("expected %v but got %v", tc.expectedSecret, result.Name)
+			}
+		})
+	}
+}
+
+func TestIsDestinationNamespaceInUse(t *testing.T) {
+	clusterSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "my-cluster-secret",
+			Namespace: sampleArgoCDNamespace,
+			Labels:    map[string]string{common.ArgoCDSecretTypeLabelKey: common.ArgoCDSecretTypeClusterValue},
+		},
+		Data: map[string][]byte{
+			"name":   []byte(sampleClusterName),
+			"server": []byte(sampleClusterServerURL),
+		},
+	}
+	newApp := func(name string, destination argoprojv1alpha1.ApplicationDestination) argoprojv1alpha1.Application {
+		return argoprojv1alpha1.Application{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: sampleArgoCDNamespace},
+			Spec:       argoprojv1alpha1.ApplicationSpec{Destination: destination},
+		}
+	}
+	byServer := argoprojv1alpha1.ApplicationDestination{Server: sampleClusterServerURL, Namespace: sampleNamespaceName}
+
+	testCases := []struct {
+		name     string
+		other    argoprojv1alpha1.ApplicationDestination
+		expected bool
+	}{
+		{
+			name:     "should be in use by an application with the same server",
+			other:    byServer,
+			expected: true,
+		},
+		{
+			name:     "should be in use by an application naming the same cluster",
+			other:    argoprojv1alpha1.ApplicationDestination{Name: sampleClusterName, Namespace: sampleNamespaceName},
+			expected: true,
+		},
+		{
+			name:     "should be in use by an application with the same server written differently",
+			other:    argoprojv1alpha1.ApplicationDestination{Server: "https://API.my-cluster.example.com:6443/", Namespace: sampleNamespaceName},
+			expected: true,
+		},
+		{
+			name:     "should not be in use by an application targeting another namespace",
+			other:    argoprojv1alpha1.ApplicationDestination{Name: sampleClusterName, Namespace: "other-namespace"},
+			expected: false,
+		},
+		{
+			name:     "should not be in use by an application targeting another cluster",
+			other:    argoprojv1alpha1.ApplicationDestination{Server: sampleFQDNServerURL, Namespace: sampleNamespaceName},
+			expected: false,
+		},
+		{
+			name:     "should be in use by an application whose destination cannot be resolved",
+			other:    argoprojv1alpha1.ApplicationDestination{Name: "missing-cluster", Namespace: sampleNamespaceName},
+			expected: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			app := newApp("app", byServer)
+			applicationList := &argoprojv1alpha1.ApplicationList{
+				Items: []argoprojv1alpha1.Application{app, newApp("other-app", tc.other)},
+			}
+			k8sClient := testutils.NewFakeClient(clusterSecret)
+
+			result, err := IsDestinationNamespaceInUse(context.Background(), k8sClient, applicationList, &app, sampleNamespaceName, sampleServerUrlDomain)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result != tc.expected {
+				t.Errorf("expected %v but got %v", tc.expected, result)
+			}
+		})
+	}
+}
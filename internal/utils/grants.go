@@ -0,0 +1,50 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	rbacv1alpha1 "github.com/dana-team/application-rbac-validator/api/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// FindActiveBypassGrant returns a BypassGrant inside the given namespace that has not expired at the given time and
+// covers the given destination cluster and namespace, or nil if there is none or the BypassGrant CRD is not installed.
+func FindActiveBypassGrant(ctx context.Context, k8sClient client.Client, namespace, clusterName, destNamespace string, now time.Time) (*rbacv1alpha1.BypassGrant, error) {
+	grantList := &rbacv1alpha1.BypassGrantList{}
+	if err := k8sClient.List(ctx, grantList, client.InNamespace(namespace)); err != nil {
+		if meta.IsNoMatchError(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to list BypassGrants in namespace %s: %w", namespace, err)
+	}
+
+	for i := range grantList.Items {
+		grant := &grantList.Items[i]
+		if !IsBypassGrantExpired(grant, now) && bypassGrantCovers(grant, clusterName, destNamespace) {
+			return grant, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// IsBypassGrantExpired returns a bool indicating whether the given grant has expired at the given time.
+func IsBypassGrantExpired(grant *rbacv1alpha1.BypassGrant, now time.Time) bool {
+	return !now.Before(grant.Spec.ExpiresAt.Time)
+}
+
+// bypassGrantCovers checks whether the given grant covers the given destination cluster and namespace. An empty
+// cluster or destination namespace in the grant covers every value. The cluster of the grant is compared by its
+// cluster name, the same way as destinations, so its case and the form it is written in do not matter and the
+// in-cluster aliases cover each other.
+func bypassGrantCovers(grant *rbacv1alpha1.BypassGrant, clusterName, destNamespace string) bool {
+	cluster := grant.Spec.Cluster
+	if cluster != "" && ExtractClusterName(cluster) != ExtractClusterName(clusterName) {
+		return false
+	}
+
+	return grant.Spec.DestinationNamespace == "" || grant.Spec.DestinationNamespace == destNamespace
+}
@@ -0,0 +1,100 @@
+package utils
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	rbacv1alpha1 "github.com/dana-team/application-rbac-validator/api/v1alpha1"
+	testutils "github.com/dana-team/application-rbac-validator/test/utils"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestFindActiveBypassGrant(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name          string
+		spec          rbacv1alpha1.BypassGrantSpec
+		clusterName   string
+		destNamespace string
+		expected      bool
+	}{
+		{
+			name:          "should find an unscoped grant",
+			spec:          rbacv1alpha1.BypassGrantSpec{ExpiresAt: metav1.NewTime(now.Add(time.Hour))},
+			clusterName:   "my-cluster",
+			destNamespace: "my-namespace",
+			expected:      true,
+		},
+		{
+			name: "should find a grant scoped to the destination",
+			spec: rbacv1alpha1.BypassGrantSpec{Cluster: "my-cluster", DestinationNamespace: "my-namespace",
+				ExpiresAt: metav1.NewTime(now.Add(time.Hour))},
+			clusterName:   "my-cluster",
+			destNamespace: "my-namespace",
+			expected:      true,
+		},
+		{
+			name:        "should match the in-cluster aliases",
+			spec:        rbacv1alpha1.BypassGrantSpec{Cluster: "in-cluster", ExpiresAt: metav1.NewTime(now.Add(time.Hour))},
+			clusterName: "kubernetes.default.svc",
+			expected:    true,
+		},
+		{
+			name:        "should ignore the case of the grant's cluster",
+			spec:        rbacv1alpha1.BypassGrantSpec{Cluster: "My-Cluster", ExpiresAt: metav1.NewTime(now.Add(time.Hour))},
+			clusterName: "my-cluster",
+			expected:    true,
+		},
+		{
+			name: "should match a grant scoped to the server URL of the cluster",
+			spec: rbacv1alpha1.BypassGrantSpec{Cluster: "https://API.my-cluster.example.com:6443",
+				ExpiresAt: metav1.NewTime(now.Add(time.Hour))},
+			clusterName: "my-cluster",
+			expected:    true,
+		},
+		{
+			name:          "should ignore a grant scoped to another cluster",
+			spec:          rbacv1alpha1.BypassGrantSpec{Cluster: "other-cluster", ExpiresAt: metav1.NewTime(now.Add(time.Hour))},
+			clusterName:   "my-cluster",
+			destNamespace: "my-namespace",
+			expected:      false,
+		},
+		{
+			name: "should ignore a grant scoped to another destination namespace",
+			spec: rbacv1alpha1.BypassGrantSpec{DestinationNamespace: "other-namespace",
+				ExpiresAt: metav1.NewTime(now.Add(time.Hour))},
+			clusterName:   "my-cluster",
+			destNamespace: "my-namespace",
+			expected:      false,
+		},
+		{
+			name:          "should ignore an expired grant",
+			spec:          rbacv1alpha1.BypassGrantSpec{ExpiresAt: metav1.NewTime(now)},
+			clusterName:   "my-cluster",
+			destNamespace: "my-namespace",
+			expected:      false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.spec.Reason = "migration"
+			tc.spec.Approver = "platform-oncall"
+			k8sClient := testutils.NewFakeClient(&rbacv1alpha1.BypassGrant{
+				ObjectMeta: metav1.ObjectMeta{Name: "grant", Namespace: samplePolicyNamespace},
+				Spec:       tc.spec,
+			})
+
+			grant, err := FindActiveBypassGrant(context.Background(), k8sClient, samplePolicyNamespace, tc.clusterName,
+				tc.destNamespace, now)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if (grant != nil) != tc.expected {
+				t.Errorf("expected %v but got %v", tc.expected, grant != nil)
+			}
+		})
+	}
+}
@@ -0,0 +1,142 @@
+package utils
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"slices"
+
+	argoprojv1alpha1 "github.com/argoproj/argo-cd/v3/pkg/apis/application/v1alpha1"
+	rbacv1alpha1 "github.com/dana-team/application-rbac-validator/api/v1alpha1"
+)
+
+// managementNameTemplateParam is the placeholder of the management Application name templates replaced with the
+// name of the Argo instance.
+const managementNameTemplateParam = "instanceName"
+
+// ManagementMatcher recognizes the management Applications of an Argo instance and the destinations they are
+// exempt from validation for.
+type ManagementMatcher struct {
+	instanceName string
+	spec         rbacv1alpha1.ManagementApplications
+	namePatterns []*regexp.Regexp
+}
+
+// NewManagementMatcher returns a ManagementMatcher for the Argo instance with the given name, configured by the
+// management Applications of the given policy. Without a policy, or when the policy does not configure them, only
+// the Application named "<argoInstanceName>-mgmt" is a management Application, for every destination.
+func NewManagementMatcher(policy *rbacv1alpha1.ApplicationRBACPolicy, argoInstanceName string) (*ManagementMatcher, error) {
+	matcher := &ManagementMatcher{instanceName: argoInstanceName}
+	if policy == nil || policy.Spec.ManagementApplications == nil {
+		return matcher, nil
+	}
+
+	matcher.spec = *policy.Spec.ManagementApplications
+	for _, pattern := range matcher.spec.NamePatterns {
+		compiled, err := regexp.Compile("^(?:" + pattern + ")$")
+		if err != nil {
+			return nil, fmt.Errorf("management Application name pattern %q is not a valid regular expression: %w", pattern, err)
+		}
+		matcher.namePatterns = append(matcher.namePatterns, compiled)
+	}
+
+	return matcher, nil
+}
+
+// ValidateManagementApplications returns an error for the first malformed name pattern or destination pattern of
+// the management Applications of the given policy, or when they are named without being restricted to allowed
+// destinations.
+func ValidateManagementApplications(policy *rbacv1alpha1.ApplicationRBACPolicy) error {
+	if _, err := NewManagementMatcher(policy, policy.Spec.InstanceName); err != nil {
+		return err
+	}
+	if policy.Spec.ManagementApplications == nil {
+		return nil
+	}
+
+	management := policy.Spec.ManagementApplications
+	if hasManagementNames(*management) && len(management.AllowedDestinations) == 0 {
+		return fmt.Errorf("management Applications set by names, name templates or name patterns require allowed destinations")
+	}
+
+	for _, destination := range policy.Spec.ManagementApplications.AllowedDestinations {
+		for _, pattern := range []string{destination.Cluster, destination.Namespace} {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return fmt.Errorf("management Application destination %q is not a valid pattern: %w", pattern, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// Matches checks whether the given Application is a management Application.
+func (m *ManagementMatcher) Matches(application *argoprojv1alpha1.Application) bool {
+	return m.matchesName(application.Name) &&
+		containsAll(application.Labels, m.spec.MatchLabels) &&
+		containsAll(application.Annotations, m.spec.MatchAnnotations)
+}
+
+// matchesName checks whether the given Application name is the name of a management Application.
+func (m *ManagementMatcher) matchesName(applicationName string) bool {
+	if !hasManagementNames(m.spec) {
+		return IsManagementApplication(m.instanceName, applicationName)
+	}
+
+	if slices.Contains(m.spec.Names, applicationName) {
+		return true
+	}
+	for _, template := range m.spec.NameTemplates {
+		name, resolved := RenderTemplateString(template, map[string]string{managementNameTemplateParam: m.instanceName})
+		if resolved && m.instanceName != "" && name == applicationName {
+			return true
+		}
+	}
+	for _, pattern := range m.namePatterns {
+		if pattern.MatchString(applicationName) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IsExemptDestination checks whether management Applications targeting the given destination are exempt from
+// validation. Without allowed destinations, only the "<instanceName>-mgmt" Application of the naming convention is
+// exempt for every destination, while management Applications set by names are exempt for none.
+func (m *ManagementMatcher) IsExemptDestination(destServer, destNamespace string) bool {
+	if len(m.spec.AllowedDestinations) == 0 {
+		return !hasManagementNames(m.spec)
+	}
+
+	for _, destination := range m.spec.AllowedDestinations {
+		if destination.Cluster != "" && !matchesCluster(destination.Cluster, destServer) {
+			continue
+		}
+		if destination.Namespace != "" {
+			if matched, err := path.Match(destination.Namespace, destNamespace); err != nil || !matched {
+				continue
+			}
+		}
+		return true
+	}
+
+	return false
+}
+
+// hasManagementNames checks whether the management Applications are set by names, name templates or name patterns
+// rather than by the naming convention.
+func hasManagementNames(spec rbacv1alpha1.ManagementApplications) bool {
+	return len(spec.Names) > 0 || len(spec.NameTemplates) > 0 || len(spec.NamePatterns) > 0
+}
+
+// containsAll checks whether the given values contain every required key with the required value.
+func containsAll(values, required map[string]string) bool {
+	for key, value := range required {
+		if actual, ok := values[key]; !ok || actual != value {
+			return false
+		}
+	}
+
+	return true
+}
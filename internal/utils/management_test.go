@@ -0,0 +1,216 @@
+package utils
+
+import (
+	"testing"
+
+	argoprojv1alpha1 "github.com/argoproj/argo-cd/v3/pkg/apis/application/v1alpha1"
+	rbacv1alpha1 "github.com/dana-team/application-rbac-validator/api/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestManagementMatcherMatches(t *testing.T) {
+	testCases := []struct {
+		name        string
+		management  *rbacv1alpha1.ManagementApplications
+		application metav1.ObjectMeta
+		expected    bool
+	}{
+		{
+			name:        "should fall back to the naming convention without configuration",
+			application: metav1.ObjectMeta{Name: "team-a-mgmt"},
+			expected:    true,
+		},
+		{
+			name:        "should recognize an Application listed by name",
+			management:  &rbacv1alpha1.ManagementApplications{Names: []string{"bootstrap"}},
+			application: metav1.ObjectMeta{Name: "bootstrap"},
+			expected:    true,
+		},
+		{
+			name:        "should not recognize the conventional name when names are configured",
+			management:  &rbacv1alpha1.ManagementApplications{Names: []string{"bootstrap"}},
+			application: metav1.ObjectMeta{Name: "team-a-mgmt"},
+			expected:    false,
+		},
+		{
+			name:        "should recognize an Application matching a name template",
+			management:  &rbacv1alpha1.ManagementApplications{NameTemplates: []string{"{{instanceName}}-bootstrap"}},
+			application: metav1.ObjectMeta{Name: "team-a-bootstrap"},
+			expected:    true,
+		},
+		{
+			name:        "should recognize an Application matching a name pattern",
+			management:  &rbacv1alpha1.ManagementApplications{NamePatterns: []string{"bootstrap-.+"}},
+			application: metav1.ObjectMeta{Name: "bootstrap-cluster-a"},
+			expected:    true,
+		},
+		{
+			name:        "should match name patterns against the whole name",
+			management:  &rbacv1alpha1.ManagementApplications{NamePatterns: []string{"bootstrap"}},
+			application: metav1.ObjectMeta{Name: "my-bootstrap-app"},
+			expected:    false,
+		},
+		{
+			name: "should recognize an Application carrying the required labels and annotations",
+			management: &rbacv1alpha1.ManagementApplications{
+				NamePatterns:     []string{".*"},
+				MatchLabels:      map[string]string{"role": "bootstrap"},
+				MatchAnnotations: map[string]string{"owner": "platform"},
+			},
+			application: metav1.ObjectMeta{
+				Name:        "cluster-a",
+				Labels:      map[string]string{"role": "bootstrap"},
+				Annotations: map[string]string{"owner": "platform"},
+			},
+			expected: true,
+		},
+		{
+			name: "should not recognize an Application missing a required label",
+			management: &rbacv1alpha1.ManagementApplications{
+				MatchLabels: map[string]string{"role": "bootstrap"},
+			},
+			application: metav1.ObjectMeta{Name: "team-a-mgmt", Labels: map[string]string{"role": "app"}},
+			expected:    false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			policy := &rbacv1alpha1.ApplicationRBACPolicy{
+				Spec: rbacv1alpha1.ApplicationRBACPolicySpec{ManagementApplications: tc.management},
+			}
+			matcher, err := NewManagementMatcher(policy, "team-a")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if matches := matcher.Matches(&argoprojv1alpha1.Application{ObjectMeta: tc.application}); matches != tc.expected {
+				t.Errorf("expected %v but got %v", tc.expected, matches)
+			}
+		})
+	}
+}
+
+func TestManagementMatcherIsExemptDestination(t *testing.T) {
+	allowedDestinations := []rbacv1alpha1.ManagementDestination{
+		{Cluster: "team-a-*", Namespace: "argocd-*"},
+		{Cluster: "https://api.shared.example.com:6443", Namespace: "team-a"},
+	}
+
+	testCases := []struct {
+		name          string
+		names         []string
+		destinations  []rbacv1alpha1.ManagementDestination
+		destServer    string
+		destNamespace string
+		expected      bool
+	}{
+		{
+			name:          "should exempt every destination when none are configured",
+			destServer:    "https://api.team-b.example.com:6443",
+			destNamespace: "kube-system",
+			expected:      true,
+		},
+		{
+			name:          "should not exempt any destination of named Applications when none are configured",
+			names:         []string{"bootstrap"},
+			destServer:    "https://api.team-b.example.com:6443",
+			destNamespace: "kube-system",
+			expected:      false,
+		},
+		{
+			name:          "should exempt a destination matching by cluster name and namespace",
+			destinations:  allowedDestinations,
+			destServer:    "https://api.team-a-dev.example.com:6443",
+			destNamespace: "argocd-apps",
+			expected:      true,
+		},
+		{
+			name:          "should exempt a destination matching by server URL",
+			destinations:  allowedDestinations,
+			destServer:    "https://api.shared.example.com:6443",
+			destNamespace: "team-a",
+			expected:      true,
+		},
+		{
+			name:          "should not exempt a managed cluster outside the managed namespaces",
+			destinations:  allowedDestinations,
+			destServer:    "https://api.team-a-dev.example.com:6443",
+			destNamespace: "kube-system",
+			expected:      false,
+		},
+		{
+			name:          "should not exempt an unmanaged cluster",
+			destinations:  allowedDestinations,
+			destServer:    "https://api.team-b.example.com:6443",
+			destNamespace: "argocd-apps",
+			expected:      false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			policy := &rbacv1alpha1.ApplicationRBACPolicy{
+				Spec: rbacv1alpha1.ApplicationRBACPolicySpec{
+					ManagementApplications: &rbacv1alpha1.ManagementApplications{Names: tc.names, AllowedDestinations: tc.destinations},
+				},
+			}
+			matcher, err := NewManagementMatcher(policy, "team-a")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if exempt := matcher.IsExemptDestination(tc.destServer, tc.destNamespace); exempt != tc.expected {
+				t.Errorf("expected %v but got %v", tc.expected, exempt)
+			}
+		})
+	}
+}
+
+func TestValidateManagementApplications(t *testing.T) {
+	testCases := []struct {
+		name        string
+		management  *rbacv1alpha1.ManagementApplications
+		expectError bool
+	}{
+		{
+			name: "should accept valid patterns",
+			management: &rbacv1alpha1.ManagementApplications{
+				NamePatterns:        []string{"bootstrap-.+"},
+				AllowedDestinations: []rbacv1alpha1.ManagementDestination{{Cluster: "team-a-*", Namespace: "argocd"}},
+			},
+		},
+		{
+			name:        "should return error for a malformed name pattern",
+			management:  &rbacv1alpha1.ManagementApplications{NamePatterns: []string{"bootstrap-("}},
+			expectError: true,
+		},
+		{
+			name:        "should return error for named Applications without allowed destinations",
+			management:  &rbacv1alpha1.ManagementApplications{NameTemplates: []string{"{{instanceName}}-bootstrap"}},
+			expectError: true,
+		},
+		{
+			name: "should return error for a malformed destination pattern",
+			management: &rbacv1alpha1.ManagementApplications{
+				AllowedDestinations: []rbacv1alpha1.ManagementDestination{{Namespace: "argocd-["}},
+			},
+			expectError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			policy := &rbacv1alpha1.ApplicationRBACPolicy{
+				Spec: rbacv1alpha1.ApplicationRBACPolicySpec{InstanceName: "team-a", ManagementApplications: tc.management},
+			}
+			err := ValidateManagementApplications(policy)
+			if tc.expectError && err == nil {
+				t.Errorf("expected error but got none")
+			}
+			if !tc.expectError && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,210 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/dana-team/application-rbac-validator/internal/common"
+	authv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+)
+
+// PermissionRule is a set of verbs a subject must be allowed to perform on a resource.
+type PermissionRule struct {
+	Group       string   `json:"group,omitempty"`
+	Resource    string   `json:"resource"`
+	Subresource string   `json:"subresource,omitempty"`
+	Verbs       []string `json:"verbs"`
+}
+
+// PermissionProfile is a named set of permission rules that defines what having access to a destination means.
+// Rules are checked in the destination namespace, while ClusterRules are checked cluster-wide for cluster-scoped
+// destinations.
+type PermissionProfile struct {
+	Name         string           `json:"-"`
+	Rules        []PermissionRule `json:"rules"`
+	ClusterRules []PermissionRule `json:"clusterRules,omitempty"`
+}
+
+// PermissionProfilesConfig is the content of the permission profiles ConfigMap. Clusters maps a destination cluster
+// name to the profile used for it, and DefaultProfile is used for clusters without a mapping.
+type PermissionProfilesConfig struct {
+	DefaultProfile string                       `json:"defaultProfile,omitempty"`
+	Profiles       map[string]PermissionProfile `json:"profiles,omitempty"`
+	Clusters       map[string]string            `json:"clusters,omitempty"`
+}
+
+// DefaultPermissionProfile returns the built-in admin profile, which requires full access to pods, and full access
+// to namespaces for cluster-scoped destinations.
+func DefaultPermissionProfile() PermissionProfile {
+	return PermissionProfile{
+		Name: common.DefaultPermissionProfileName,
+		Rules: []PermissionRule{
+			{
+				Resource: common.InstanceUsersAccessLevelResource,
+				Verbs:    common.InstanceUsersAccessLevelVerbs,
+			},
+		},
+		ClusterRules: []PermissionRule{
+			{
+				Resource: common.ClusterScopedAccessLevelResource,
+				Verbs:    common.InstanceUsersAccessLevelVerbs,
+			},
+		},
+	}
+}
+
+// String returns a short description of the profile's rules, e.g. "admin (pods: get,list; apps/deployments: get)".
+func (p PermissionProfile) String() string {
+	return fmt.Sprintf("%s (%s)", p.Name, describeRules(p.Rules))
+}
+
+// ClusterString returns a short description of the profile's cluster-scoped rules.
+func (p PermissionProfile) ClusterString() string {
+	return fmt.Sprintf("%s (%s)", p.Name, describeRules(p.ClusterRules))
+}
+
+// describeRules returns a short description of the given rules.
+func describeRules(permissionRules []PermissionRule) string {
+	rules := make([]string, 0, len(permissionRules))
+	for _, rule := range permissionRules {
+		resource := rule.Resource
+		if rule.Subresource != "" {
+			resource += "/" + rule.Subresource
+		}
+		if rule.Group != "" {
+			resource = rule.Group + "/" + resource
+		}
+		rules = append(rules, resource+": "+strings.Join(rule.Verbs, ","))
+	}
+
+	return strings.Join(rules, "; ")
+}
+
+// ParsePermissionProfilesConfig parses and validates the YAML content of the permission profiles ConfigMap.
+func ParsePermissionProfilesConfig(data string) (*PermissionProfilesConfig, error) {
+	config := &PermissionProfilesConfig{}
+	if err := yaml.UnmarshalStrict([]byte(data), config); err != nil {
+		return nil, fmt.Errorf("failed to parse permission profiles: %w", err)
+	}
+
+	for name, profile := range config.Profiles {
+		if len(profile.Rules) == 0 {
+			return nil, fmt.Errorf("permission profile %q must have at least one rule", name)
+		}
+		for _, rule := range slices.Concat(profile.Rules, profile.ClusterRules) {
+			if rule.Resource == "" || len(rule.Verbs) == 0 {
+				return nil, fmt.Errorf("permission profile %q has a rule without a resource or verbs", name)
+			}
+		}
+	}
+
+	return config, nil
+}
+
+// FetchPermissionProfilesConfig fetches the permission profiles ConfigMap from the given namespace. An empty
+// configuration is returned when the ConfigMap does not exist.
+func FetchPermissionProfilesConfig(ctx context.Context, k8sClient client.Client, namespace string) (*PermissionProfilesConfig, error) {
+	var cm corev1.ConfigMap
+	if err := k8sClient.Get(ctx, client.ObjectKey{Namespace: namespace, Name: common.PermissionProfilesConfigMapName}, &cm); err != nil {
+		if apierrors.IsNotFound(err) {
+			return &PermissionProfilesConfig{}, nil
+		}
+		return nil, fmt.Errorf("failed to get ConfigMap %q: %w", common.PermissionProfilesConfigMapName, err)
+	}
+
+	return ParsePermissionProfilesConfig(cm.Data[common.PermissionProfilesConfigMapKey])
+}
+
+// Profile returns the permission profile with the given name. The built-in admin profile is available unless the
+// configuration overrides it.
+func (c *PermissionProfilesConfig) Profile(name string) (PermissionProfile, error) {
+	if profile, ok := c.Profiles[name]; ok {
+		profile.Name = name
+		return profile, nil
+	}
+	if name == common.DefaultPermissionProfileName {
+		return DefaultPermissionProfile(), nil
+	}
+
+	return PermissionProfile{}, fmt.Errorf("permission profile %q is not defined", name)
+}
+
+// ResolvePermissionProfile returns the permission profile used to validate access to the given destination cluster.
+// The profile mapped to the cluster by the administrator takes precedence over the profile named in the Argo
+// instance's ApplicationRBACPolicy or argo-config ConfigMap, which takes precedence over the configured default profile
+// and finally the built-in admin profile.
+func ResolvePermissionProfile(ctx context.Context, k8sClient client.Client, currentNamespace, appNamespace, clusterName string) (PermissionProfile, error) {
+	config, err := FetchPermissionProfilesConfig(ctx, k8sClient, currentNamespace)
+	if err != nil {
+		return PermissionProfile{}, err
+	}
+
+	instanceProfile, err := fetchInstancePermissionProfile(ctx, k8sClient, appNamespace)
+	if err != nil {
+		return PermissionProfile{}, err
+	}
+
+	switch {
+	case config.Clusters[clusterName] != "":
+		return config.Profile(config.Clusters[clusterName])
+	case instanceProfile != "":
+		return config.Profile(instanceProfile)
+	case config.DefaultProfile != "":
+		return config.Profile(config.DefaultProfile)
+	default:
+		return config.Profile(common.DefaultPermissionProfileName)
+	}
+}
+
+// fetchInstancePermissionProfile returns the permission profile the Argo instance picked in its
+// ApplicationRBACPolicy, falling back to its argo-config ConfigMap, or an empty value if it did not pick one.
+func fetchInstancePermissionProfile(ctx context.Context, k8sClient client.Client, appNamespace string) (string, error) {
+	policy, err := FetchApplicationRBACPolicy(ctx, k8sClient, appNamespace)
+	if err != nil {
+		return "", err
+	}
+	if policy != nil {
+		return policy.Spec.PermissionProfile, nil
+	}
+
+	return fetchOptionalConfigMapValue(ctx, k8sClient, appNamespace, common.ArgoInstanceConfigMapName,
+		common.PermissionProfileConfigMapKey)
+}
+
+// fetchOptionalConfigMapValue fetches a value from a ConfigMap, returning an empty value if the ConfigMap or the key
+// does not exist.
+func fetchOptionalConfigMapValue(ctx context.Context, k8sClient client.Client, namespace, configMapName, key string) (string, error) {
+	var cm corev1.ConfigMap
+	if err := k8sClient.Get(ctx, client.ObjectKey{Namespace: namespace, Name: configMapName}, &cm); err != nil {
+		if apierrors.IsNotFound(err) {
+			return "", nil
+		}
+		return "", fmt.Errorf("failed to get ConfigMap %q: %w", configMapName, err)
+	}
+
+	return strings.TrimSpace(cm.Data[key]), nil
+}
+
+// buildSubjectAccessReview creates a SubjectAccessReview for the given subject, rule and verb.
+func buildSubjectAccessReview(subject Subject, namespace string, rule PermissionRule, verb string) *authv1.SubjectAccessReview {
+	user, groups := subject.UserInfo()
+	return &authv1.SubjectAccessReview{
+		Spec: authv1.SubjectAccessReviewSpec{
+			User:   user,
+			Groups: groups,
+			ResourceAttributes: &authv1.ResourceAttributes{
+				Namespace:   namespace,
+				Verb:        verb,
+				Group:       rule.Group,
+				Resource:    rule.Resource,
+				Subresource: rule.Subresource,
+			},
+		},
+	}
+}
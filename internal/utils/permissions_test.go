@@ -0,0 +1,297 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+
+	"github.com/dana-team/application-rbac-validator/internal/common"
+	"github.com/dana-team/application-rbac-validator/internal/decision"
+	testutils "github.com/dana-team/application-rbac-validator/test/utils"
+	authv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const samplePermissionProfiles = `
+defaultProfile: deployer
+profiles:
+  readonly:
+    rules:
+      - resource: pods
+        verbs: ["get", "list", "watch"]
+  deployer:
+    rules:
+      - group: apps
+        resource: deployments
+        verbs: ["get", "create"]
+      - resource: pods
+        subresource: log
+        verbs: ["get"]
+clusters:
+  my-cluster: readonly
+`
+
+func TestParsePermissionProfilesConfig(t *testing.T) {
+	testCases := []struct {
+		name        string
+		data        string
+		expectError bool
+	}{
+		{
+			name: "should parse valid profiles",
+			data: samplePermissionProfiles,
+		},
+		{
+			name: "should parse an empty configuration",
+			data: "",
+		},
+		{
+			name:        "should return error for a profile without rules",
+			data:        "profiles:\n  empty: {}\n",
+			expectError: true,
+		},
+		{
+			name:        "should return error for a rule without verbs",
+			data:        "profiles:\n  broken:\n    rules:\n      - resource: pods\n",
+			expectError: true,
+		},
+		{
+			name:        "should return error for unknown fields",
+			data:        "profile: readonly\n",
+			expectError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := ParsePermissionProfilesConfig(tc.data)
+			if tc.expectError && err == nil {
+				t.Errorf("expected error but got none")
+			}
+			if !tc.expectError && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestResolvePermissionProfile(t *testing.T) {
+	profilesConfigMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      common.PermissionProfilesConfigMapName,
+			Namespace: sampleNamespaceObjectName,
+		},
+		Data: map[string]string{
+			common.PermissionProfilesConfigMapKey: samplePermissionProfiles,
+		},
+	}
+	argoConfigMap := func(profile string) *corev1.ConfigMap {
+		return &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      common.ArgoInstanceConfigMapName,
+				Namespace: sampleNamespaceName,
+			},
+			Data: map[string]string{
+				common.PermissionProfileConfigMapKey: profile,
+			},
+		}
+	}
+
+	testCases := []struct {
+		name          string
+		existingObjs  []client.Object
+		clusterName   string
+		expected      string
+		expectedRules int
+		expectError   bool
+	}{
+		{
+			name:          "should default to the built-in admin profile",
+			clusterName:   sampleClusterName,
+			expected:      common.DefaultPermissionProfileName,
+			expectedRules: 1,
+		},
+		{
+			name:          "should use the profile mapped to the cluster",
+			existingObjs:  []client.Object{profilesConfigMap},
+			clusterName:   sampleClusterName,
+			expected:      "readonly",
+			expectedRules: 1,
+		},
+		{
+			name:          "should use the default profile for unmapped clusters",
+			existingObjs:  []client.Object{profilesConfigMap},
+			clusterName:   "other-cluster",
+			expected:      "deployer",
+			expectedRules: 2,
+		},
+		{
+			name:          "should prefer the profile mapped to the cluster over the argo instance profile",
+			existingObjs:  []client.Object{profilesConfigMap, argoConfigMap(common.DefaultPermissionProfileName)},
+			clusterName:   sampleClusterName,
+			expected:      "readonly",
+			expectedRules: 1,
+		},
+		{
+			name:          "should prefer the argo instance profile over the default profile for unmapped clusters",
+			existingObjs:  []client.Object{profilesConfigMap, argoConfigMap(common.DefaultPermissionProfileName)},
+			clusterName:   "other-cluster",
+			expected:      common.DefaultPermissionProfileName,
+			expectedRules: 1,
+		},
+		{
+			name:         "should return error for an undefined profile",
+			existingObjs: []client.Object{argoConfigMap("missing")},
+			clusterName:  sampleClusterName,
+			expectError:  true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cl := testutils.NewFakeClient(tc.existingObjs...)
+
+			result, err := ResolvePermissionProfile(context.Background(), cl, sampleNamespaceObjectName, sampleNamespaceName, tc.clusterName)
+			if tc.expectError && err == nil {
+				t.Errorf("expected error but got none")
+			}
+			if !tc.expectError && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if tc.expectError {
+				return
+			}
+			if result.Name != tc.expected {
+				t.Errorf("expected profile %q but got %q", tc.expected, result.Name)
+			}
+			if len(result.Rules) != tc.expectedRules {
+				t.Errorf("expected %d rules but got %d", tc.expectedRules, len(result.Rules))
+			}
+		})
+	}
+}
+
+func TestEnsureAnyAdminHasClusterAccess(t *testing.T) {
+	testCases := []struct {
+		name        string
+		admins      []Subject
+		profile     PermissionProfile
+		expectError bool
+	}{
+		{
+			name:    "should allow when an admin has cluster-scoped access",
+			admins:  []Subject{{Kind: common.SubjectKindUser, Name: "admin2"}, {Kind: common.SubjectKindUser, Name: "admin1"}},
+			profile: DefaultPermissionProfile(),
+		},
+		{
+			name:        "should reject when no admin has cluster-scoped access",
+			admins:      []Subject{{Kind: common.SubjectKindUser, Name: "admin2"}},
+			profile:     DefaultPermissionProfile(),
+			expectError: true,
+		},
+		{
+			name:        "should reject when the profile has no cluster-scoped rules",
+			admins:      []Subject{{Kind: common.SubjectKindUser, Name: "admin1"}},
+			profile:     PermissionProfile{Name: "readonly", Rules: DefaultPermissionProfile().Rules},
+			expectError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := EnsureAnyAdminHasClusterAccess(context.Background(), testutils.NewMockedDestinationClusterClient(),
+				tc.admins, tc.profile, sampleClusterServerURL)
+			if tc.expectError && err == nil {
+				t.Errorf("expected error but got none")
+			}
+			if !tc.expectError && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestEnsureAnyAdminHasNamespaceAccess(t *testing.T) {
+	const allowedAdmin = "allowed-admin"
+
+	deniedAdmins := func(count int) []Subject {
+		admins := make([]Subject, 0, count)
+		for i := range count {
+			admins = append(admins, Subject{Kind: common.SubjectKindUser, Name: fmt.Sprintf("denied-admin-%d", i)})
+		}
+		return admins
+	}
+
+	testCases := []struct {
+		name             string
+		admins           []Subject
+		expectError      bool
+		maxAccessReviews int64
+	}{
+		{
+			name:             "should check a single verb per denied admin",
+			admins:           deniedAdmins(20),
+			expectError:      true,
+			maxAccessReviews: 20,
+		},
+		{
+			name:             "should stop checking once an admin is allowed",
+			admins:           append([]Subject{{Kind: common.SubjectKindUser, Name: allowedAdmin}}, deniedAdmins(20)...),
+			maxAccessReviews: 20 + int64(len(common.InstanceUsersAccessLevelVerbs)),
+		},
+		{
+			name:             "should allow when the last admin is allowed",
+			admins:           append(deniedAdmins(20), Subject{Kind: common.SubjectKindUser, Name: allowedAdmin}),
+			maxAccessReviews: 20 + int64(len(common.InstanceUsersAccessLevelVerbs)),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var accessReviews atomic.Int64
+			destinationClient := fake.NewClientset()
+			destinationClient.PrependReactor("create", "subjectaccessreviews",
+				func(action k8stesting.Action) (bool, runtime.Object, error) {
+					accessReviews.Add(1)
+					sar := action.(k8stesting.CreateAction).GetObject().(*authv1.SubjectAccessReview)
+					return true, &authv1.SubjectAccessReview{
+						Status: authv1.SubjectAccessReviewStatus{Allowed: sar.Spec.User == allowedAdmin},
+					}, nil
+				})
+
+			err := EnsureAnyAdminHasNamespaceAccess(context.Background(), destinationClient, tc.admins,
+				DefaultPermissionProfile(), sampleNamespaceName, sampleClusterServerURL)
+			if tc.expectError && err == nil {
+				t.Errorf("expected error but got none")
+			}
+			if !tc.expectError && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if accessReviews.Load() > tc.maxAccessReviews {
+				t.Errorf("expected at most %d SubjectAccessReviews but got %d", tc.maxAccessReviews, accessReviews.Load())
+			}
+		})
+	}
+}
+
+func TestEnsureAnyAdminHasNamespaceAccessWithEndedContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	admins := []Subject{{Kind: common.SubjectKindUser, Name: "admin1"}, {Kind: common.SubjectKindUser, Name: "admin2"}}
+	err := EnsureAnyAdminHasNamespaceAccess(ctx, fake.NewClientset(), admins, DefaultPermissionProfile(),
+		sampleNamespaceName, sampleClusterServerURL)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected a context canceled error but got %v", err)
+	}
+	if reason := decision.ReasonOf(err); reason != decision.ReasonDestinationUnreachable {
+		t.Errorf("expected reason %v but got %v", decision.ReasonDestinationUnreachable, reason)
+	}
+}
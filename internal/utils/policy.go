@@ -0,0 +1,89 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+
+	rbacv1alpha1 "github.com/dana-team/application-rbac-validator/api/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// FetchApplicationRBACPolicy returns the ApplicationRBACPolicy inside the given namespace, or nil if there is none or
+// the ApplicationRBACPolicy CRD is not installed, in which case the argo-config ConfigMap is used. It returns an error
+// if the namespace holds more than one policy.
+func FetchApplicationRBACPolicy(ctx context.Context, k8sClient client.Client, namespace string) (*rbacv1alpha1.ApplicationRBACPolicy, error) {
+	policyList := &rbacv1alpha1.ApplicationRBACPolicyList{}
+	if err := k8sClient.List(ctx, policyList, client.InNamespace(namespace)); err != nil {
+		if meta.IsNoMatchError(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to list ApplicationRBACPolicies in namespace %s: %w", namespace, err)
+	}
+
+	switch len(policyList.Items) {
+	case 0:
+		return nil, nil
+	case 1:
+		return &policyList.Items[0], nil
+	default:
+		return nil, fmt.Errorf("namespace %s holds %d ApplicationRBACPolicies, expected at most one", namespace,
+			len(policyList.Items))
+	}
+}
+
+// PolicySubjects converts the admins of the given policy into subjects.
+func PolicySubjects(policy *rbacv1alpha1.ApplicationRBACPolicy) []Subject {
+	subjects := make([]Subject, 0, len(policy.Spec.Admins))
+	for _, admin := range policy.Spec.Admins {
+		subjects = append(subjects, Subject{
+			Kind:      strings.ToLower(admin.Kind),
+			Name:      admin.Name,
+			Namespace: admin.Namespace,
+		})
+	}
+
+	return subjects
+}
+
+// IsClusterAllowed checks whether the given policy allows the given destination server, matching its allowed
+// clusters against both the cluster name and the server URL. A nil policy or an empty list allows every cluster.
+func IsClusterAllowed(policy *rbacv1alpha1.ApplicationRBACPolicy, destServer string) bool {
+	if policy == nil || len(policy.Spec.AllowedClusters) == 0 {
+		return true
+	}
+
+	for _, pattern := range policy.Spec.AllowedClusters {
+		if matchesCluster(pattern, destServer) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// matchesCluster checks whether the given glob pattern matches either the cluster name or the server URL of the
+// given destination server.
+func matchesCluster(pattern, destServer string) bool {
+	for _, value := range []string{ExtractClusterName(destServer), strings.TrimSuffix(destServer, "/")} {
+		if matched, err := path.Match(pattern, value); err == nil && matched {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ValidateAllowedClusters returns an error for the first allowed cluster of the given policy that is a malformed
+// pattern.
+func ValidateAllowedClusters(policy *rbacv1alpha1.ApplicationRBACPolicy) error {
+	for _, pattern := range policy.Spec.AllowedClusters {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("allowed cluster %q is not a valid pattern: %w", pattern, err)
+		}
+	}
+
+	return nil
+}
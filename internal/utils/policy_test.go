@@ -0,0 +1,156 @@
+package utils
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	rbacv1alpha1 "github.com/dana-team/application-rbac-validator/api/v1alpha1"
+	"github.com/dana-team/application-rbac-validator/internal/common"
+	testutils "github.com/dana-team/application-rbac-validator/test/utils"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const samplePolicyNamespace = "argocd-team-a"
+
+// newTestPolicy returns an ApplicationRBACPolicy with the given name and spec in the sample policy namespace.
+func newTestPolicy(name string, spec rbacv1alpha1.ApplicationRBACPolicySpec) *rbacv1alpha1.ApplicationRBACPolicy {
+	return &rbacv1alpha1.ApplicationRBACPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: samplePolicyNamespace},
+		Spec:       spec,
+	}
+}
+
+func TestFetchApplicationRBACPolicy(t *testing.T) {
+	spec := rbacv1alpha1.ApplicationRBACPolicySpec{
+		InstanceName: "team-a",
+		Admins:       []rbacv1alpha1.PolicySubject{{Kind: "User", Name: "alice"}},
+	}
+
+	testCases := []struct {
+		name           string
+		objects        []client.Object
+		expectedPolicy string
+		expectError    bool
+	}{
+		{
+			name:           "should return nil when the namespace holds no policy",
+			expectedPolicy: "",
+		},
+		{
+			name:           "should return the policy of the namespace",
+			objects:        []client.Object{newTestPolicy("policy", spec)},
+			expectedPolicy: "policy",
+		},
+		{
+			name:        "should return error when the namespace holds more than one policy",
+			objects:     []client.Object{newTestPolicy("policy", spec), newTestPolicy("other-policy", spec)},
+			expectError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			k8sClient := testutils.NewFakeClient(tc.objects...)
+
+			policy, err := FetchApplicationRBACPolicy(context.Background(), k8sClient, samplePolicyNamespace)
+			if tc.expectError {
+				if err == nil {
+					t.Errorf("expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			name := ""
+			if policy != nil {
+				name = policy.Name
+			}
+			if name != tc.expectedPolicy {
+				t.Errorf("expected policy %q but got %q", tc.expectedPolicy, name)
+			}
+		})
+	}
+}
+
+func TestFetchArgoInstanceUsersPrefersPolicy(t *testing.T) {
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: common.ArgoInstanceConfigMapName, Namespace: samplePolicyNamespace},
+		Data:       map[string]string{common.ArgoInstanceUsersConfigMapKey: "bob"},
+	}
+	policy := newTestPolicy("policy", rbacv1alpha1.ApplicationRBACPolicySpec{
+		InstanceName: "team-a",
+		Admins: []rbacv1alpha1.PolicySubject{
+			{Kind: "User", Name: "alice"},
+			{Kind: "ServiceAccount", Namespace: "ci", Name: "deployer"},
+		},
+	})
+	k8sClient := testutils.NewFakeClient(configMap, policy)
+
+	users, err := FetchArgoInstanceUsers(context.Background(), k8sClient, samplePolicyNamespace)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []Subject{
+		{Kind: "user", Name: "alice"},
+		{Kind: "serviceaccount", Namespace: "ci", Name: "deployer"},
+	}
+	if !slices.Equal(users, expected) {
+		t.Errorf("expected %v but got %v", expected, users)
+	}
+
+	instanceName, err := FetchArgoInstanceName(context.Background(), k8sClient, samplePolicyNamespace)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if instanceName != "team-a" {
+		t.Errorf("expected %v but got %v", "team-a", instanceName)
+	}
+}
+
+func TestIsClusterAllowed(t *testing.T) {
+	testCases := []struct {
+		name            string
+		allowedClusters []string
+		destServer      string
+		expected        bool
+	}{
+		{
+			name:       "should allow every cluster when the list is empty",
+			destServer: "https://api.my-cluster.example.com:6443",
+			expected:   true,
+		},
+		{
+			name:            "should allow a cluster matching by name",
+			allowedClusters: []string{"team-a-*"},
+			destServer:      "https://api.team-a-dev.example.com:6443",
+			expected:        true,
+		},
+		{
+			name:            "should allow a cluster matching by server URL",
+			allowedClusters: []string{"https://api.my-cluster.example.com:6443"},
+			destServer:      "https://api.my-cluster.example.com:6443/",
+			expected:        true,
+		},
+		{
+			name:            "should reject a cluster matching no pattern",
+			allowedClusters: []string{"team-a-*"},
+			destServer:      "https://api.team-b-dev.example.com:6443",
+			expected:        false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			policy := newTestPolicy("policy", rbacv1alpha1.ApplicationRBACPolicySpec{AllowedClusters: tc.allowedClusters})
+			if allowed := IsClusterAllowed(policy, tc.destServer); allowed != tc.expected {
+				t.Errorf("expected %v but got %v", tc.expected, allowed)
+			}
+		})
+	}
+}
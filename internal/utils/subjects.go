@@ -0,0 +1,97 @@
+package utils
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dana-team/application-rbac-validator/internal/common"
+)
+
+// Subject is an entry of the argo instance users, identifying either a user, a group or a ServiceAccount.
+type Subject struct {
+	Kind      string
+	Name      string
+	Namespace string
+}
+
+// ParseSubject parses a single instance_users entry. Entries are formatted as "user:<name>", "group:<name>" or
+// "serviceaccount:<namespace>/<name>", and entries without a kind prefix are treated as users.
+func ParseSubject(value string) (Subject, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return Subject{}, fmt.Errorf("subject must not be empty")
+	}
+
+	kind, name, found := strings.Cut(value, ":")
+	if !found || !isSubjectKind(kind) {
+		return Subject{Kind: common.SubjectKindUser, Name: value}, nil
+	}
+
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return Subject{}, fmt.Errorf("subject %q is missing a name", value)
+	}
+
+	if kind != common.SubjectKindServiceAccount {
+		return Subject{Kind: kind, Name: name}, nil
+	}
+
+	namespace, saName, found := strings.Cut(name, "/")
+	if !found || namespace == "" || saName == "" {
+		return Subject{}, fmt.Errorf("ServiceAccount subject %q must be formatted as %s:<namespace>/<name>",
+			value, common.SubjectKindServiceAccount)
+	}
+
+	return Subject{Kind: kind, Name: saName, Namespace: namespace}, nil
+}
+
+// isSubjectKind returns a bool indicating whether the given prefix is a supported subject kind.
+func isSubjectKind(kind string) bool {
+	return kind == common.SubjectKindUser || kind == common.SubjectKindGroup || kind == common.SubjectKindServiceAccount
+}
+
+// ParseSubjects parses a comma separated list of instance_users entries, skipping empty entries.
+func ParseSubjects(value string) ([]Subject, error) {
+	var subjects []Subject
+	for _, entry := range strings.Split(value, ",") {
+		if strings.TrimSpace(entry) == "" {
+			continue
+		}
+
+		subject, err := ParseSubject(entry)
+		if err != nil {
+			return nil, err
+		}
+		subjects = append(subjects, subject)
+	}
+
+	return subjects, nil
+}
+
+// String returns the subject in the format it is configured with.
+func (s Subject) String() string {
+	switch s.Kind {
+	case common.SubjectKindGroup:
+		return common.SubjectKindGroup + ":" + s.Name
+	case common.SubjectKindServiceAccount:
+		return common.SubjectKindServiceAccount + ":" + s.Namespace + "/" + s.Name
+	default:
+		return s.Name
+	}
+}
+
+// UserInfo returns the user and groups the subject is represented by in a SubjectAccessReview. ServiceAccounts are
+// represented by their "system:serviceaccount:" user name along with the groups Kubernetes assigns to them.
+func (s Subject) UserInfo() (string, []string) {
+	switch s.Kind {
+	case common.SubjectKindGroup:
+		return "", []string{s.Name}
+	case common.SubjectKindServiceAccount:
+		return common.ServiceAccountUsernamePrefix + s.Namespace + ":" + s.Name, []string{
+			common.ServiceAccountsGroup,
+			common.ServiceAccountsGroup + ":" + s.Namespace,
+		}
+	default:
+		return s.Name, nil
+	}
+}
@@ -0,0 +1,114 @@
+package utils
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/dana-team/application-rbac-validator/internal/common"
+)
+
+func TestParseSubject(t *testing.T) {
+	testCases := []struct {
+		name        string
+		value       string
+		expected    Subject
+		expectError bool
+	}{
+		{
+			name:     "should treat an entry without a kind as a user",
+			value:    sampleUser,
+			expected: Subject{Kind: common.SubjectKindUser, Name: sampleUser},
+		},
+		{
+			name:     "should parse an explicit user",
+			value:    " user:" + sampleUser + " ",
+			expected: Subject{Kind: common.SubjectKindUser, Name: sampleUser},
+		},
+		{
+			name:     "should keep unknown prefixes as part of the user name",
+			value:    "oidc:" + sampleUser,
+			expected: Subject{Kind: common.SubjectKindUser, Name: "oidc:" + sampleUser},
+		},
+		{
+			name:     "should parse a group",
+			value:    "group:platform-admins",
+			expected: Subject{Kind: common.SubjectKindGroup, Name: "platform-admins"},
+		},
+		{
+			name:     "should parse a ServiceAccount",
+			value:    "serviceaccount:ci/deployer",
+			expected: Subject{Kind: common.SubjectKindServiceAccount, Name: "deployer", Namespace: "ci"},
+		},
+		{
+			name:        "should return error for a ServiceAccount without a namespace",
+			value:       "serviceaccount:deployer",
+			expectError: true,
+		},
+		{
+			name:        "should return error for a group without a name",
+			value:       "group:",
+			expectError: true,
+		},
+		{
+			name:        "should return error for an empty subject",
+			value:       " ",
+			expectError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := ParseSubject(tc.value)
+			if tc.expectError && err == nil {
+				t.Errorf("expected error but got none")
+			}
+			if !tc.expectError && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if !tc.expectError && result != tc.expected {
+				t.Errorf("expected %v but got %v", tc.expected, result)
+			}
+		})
+	}
+}
+
+func TestBuildSubjectAccessReview(t *testing.T) {
+	testCases := []struct {
+		name           string
+		subject        Subject
+		expectedUser   string
+		expectedGroups []string
+	}{
+		{
+			name:         "should set the user for a user subject",
+			subject:      Subject{Kind: common.SubjectKindUser, Name: sampleUser},
+			expectedUser: sampleUser,
+		},
+		{
+			name:           "should set the groups for a group subject",
+			subject:        Subject{Kind: common.SubjectKindGroup, Name: "platform-admins"},
+			expectedGroups: []string{"platform-admins"},
+		},
+		{
+			name:           "should use the ServiceAccount user name and groups",
+			subject:        Subject{Kind: common.SubjectKindServiceAccount, Name: "deployer", Namespace: "ci"},
+			expectedUser:   "system:serviceaccount:ci:deployer",
+			expectedGroups: []string{"system:serviceaccounts", "system:serviceaccounts:ci"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			sar := buildSubjectAccessReview(tc.subject, sampleNamespaceName, DefaultPermissionProfile().Rules[0], "get")
+			if sar.Spec.User != tc.expectedUser {
+				t.Errorf("expected user %q but got %q", tc.expectedUser, sar.Spec.User)
+			}
+			if !slices.Equal(sar.Spec.Groups, tc.expectedGroups) {
+				t.Errorf("expected groups %v but got %v", tc.expectedGroups, sar.Spec.Groups)
+			}
+			if sar.Spec.ResourceAttributes.Namespace != sampleNamespaceName {
+				t.Errorf("expected namespace %q but got %q", sampleNamespaceName, sar.Spec.ResourceAttributes.Namespace)
+			}
+		})
+	}
+}
@@ -6,12 +6,19 @@ import (
 	"net/url"
 	"os"
 	"reflect"
+	"slices"
 	"strings"
+	"sync"
+	"sync/atomic"
 
 	argoprojv1alpha1 "github.com/argoproj/argo-cd/v3/pkg/apis/application/v1alpha1"
 	"github.com/dana-team/application-rbac-validator/internal/common"
-	authv1 "k8s.io/api/authorization/v1"
+	"github.com/dana-team/application-rbac-validator/internal/decision"
+	"github.com/dana-team/application-rbac-validator/internal/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"golang.org/x/sync/errgroup"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes"
@@ -26,6 +33,25 @@ func IsNotSpecUpdate(oldApp, newApp *argoprojv1alpha1.Application) bool {
 	return reflect.DeepEqual(oldApp.Spec, newApp.Spec)
 }
 
+// IsAutoSyncDisableUpdate checks if the only change to the Application's spec is disabling its automated sync,
+// which cannot grant access to a destination.
+func IsAutoSyncDisableUpdate(oldApp, newApp *argoprojv1alpha1.Application) bool {
+	if oldApp.Spec.SyncPolicy == nil || oldApp.Spec.SyncPolicy.Automated == nil || !IsAutoSyncDisabled(newApp) {
+		return false
+	}
+	oldSpec := oldApp.Spec.DeepCopy()
+	oldSpec.SyncPolicy.Automated.Enabled = newApp.Spec.SyncPolicy.Automated.Enabled
+
+	return reflect.DeepEqual(*oldSpec, newApp.Spec)
+}
+
+// IsAutoSyncDisabled checks if the Application's automated sync is configured but explicitly disabled.
+func IsAutoSyncDisabled(app *argoprojv1alpha1.Application) bool {
+	syncPolicy := app.Spec.SyncPolicy
+	return syncPolicy != nil && syncPolicy.Automated != nil && syncPolicy.Automated.Enabled != nil &&
+		!*syncPolicy.Automated.Enabled
+}
+
 // ValidateServerUrlFormat checks whether the given destServer is a full, valid server URL according to this format:
 // https://api.my-cluster.domain.example.com:port.
 func ValidateServerUrlFormat(destServer string) bool {
@@ -46,20 +72,10 @@ func ValidateServerUrlFormat(destServer string) bool {
 	return true
 }
 
-// ExtractClusterName parses the destServer URL and returns the cluster name (e.g. "my-cluster")
-// if the URL is valid and matches the expected format.
+// ExtractClusterName returns the short cluster name (e.g. "my-cluster") of the given destination server, which may
+// be a server URL, a short cluster name or an in-cluster alias. It is the first label of its destination key.
 func ExtractClusterName(destServer string) string {
-	if IsInCluster(destServer) {
-		return common.InClusterValues[0]
-	}
-	if ValidateServerUrlFormat(destServer) {
-		parsedUrl, _ := url.Parse(destServer)
-		host := parsedUrl.Hostname()
-		parts := strings.Split(host, ".")
-		return parts[1]
-	}
-
-	return destServer
+	return strings.Split(DestinationKey(destServer, ""), ".")[0]
 }
 
 // BuildServerUrl constructs a full server URL from a partial cluster name according to this format:
@@ -85,8 +101,14 @@ func GetCurrentNamespace() (string, error) {
 //     and returns data['server'].
 //  4. Returns error if neither is resolvable.
 func ResolveDestinationServer(ctx context.Context, c client.Client, app *argoprojv1alpha1.Application) (string, error) {
-	destServer := app.Spec.Destination.Server
-	destName := app.Spec.Destination.Name
+	return ResolveServer(ctx, c, app.Namespace, app.Spec.Destination)
+}
+
+// ResolveServer resolves the server URL of the given destination the same way as ResolveDestinationServer,
+// looking up named destinations in the Argo CD cluster secrets of the given namespace.
+func ResolveServer(ctx context.Context, c client.Client, namespace string, destination argoprojv1alpha1.ApplicationDestination) (string, error) {
+	destServer := destination.Server
+	destName := destination.Name
 	if destServer != "" {
 		return destServer, nil
 	}
@@ -101,8 +123,8 @@ func ResolveDestinationServer(ctx context.Context, c client.Client, app *argopro
 			common.ArgoCDSecretTypeLabelKey: common.ArgoCDSecretTypeClusterValue,
 		}
 
-		if err := c.List(ctx, secretList, client.InNamespace(app.Namespace), labelSelector); err != nil {
-			return "", fmt.Errorf("failed to list cluster secrets in namespace %s: %w", app.Namespace, err)
+		if err := c.List(ctx, secretList, client.InNamespace(namespace), labelSelector); err != nil {
+			return "", fmt.Errorf("failed to list cluster secrets in namespace %s: %w", namespace, err)
 		}
 
 		for _, secret := range secretList.Items {
@@ -116,12 +138,24 @@ func ResolveDestinationServer(ctx context.Context, c client.Client, app *argopro
 			}
 		}
 
-		return "", fmt.Errorf("destination cluster with name %q not found in namespace %q", destName, app.Namespace)
+		return "", fmt.Errorf("destination cluster with name %q not found in namespace %q", destName, namespace)
 	}
 
 	return "", fmt.Errorf("destination server or name must be specified")
 }
 
+// IsGlobPattern checks if the given AppProject destination value is a glob pattern (e.g. "*" or "team-?")
+// rather than a concrete server, name or namespace.
+func IsGlobPattern(value string) bool {
+	return strings.ContainsAny(value, "*?[")
+}
+
+// IsDenyPattern checks if the given AppProject destination value is a negated entry (e.g. "!kube-system"),
+// which restricts the project instead of granting it access.
+func IsDenyPattern(value string) bool {
+	return strings.HasPrefix(value, "!")
+}
+
 // IsManagementApplication checks whether the given application name
 // follows the pattern "<argoInstanceName>-mgmt".
 func IsManagementApplication(argoInstanceName, applicationName string) bool {
@@ -171,15 +205,94 @@ func isBypassLabelValid(labels map[string]string, clusterName string) bool {
 	return false
 }
 
-// IsInCluster checks if the given value equals any known in-cluster values (e.g., "in-cluster", "kubernetes.default.svc.cluster.local").
-func IsInCluster(server string) bool {
-	for _, val := range common.InClusterValues {
-		if strings.Contains(server, val) {
-			return true
+// IsValidEnforcementMode checks if the given value is a known enforcement mode.
+func IsValidEnforcementMode(mode string) bool {
+	return mode == common.EnforcementModeEnforce || mode == common.EnforcementModeWarn
+}
+
+// ParseClusterEnforcementModes parses a comma separated list of "<cluster>=<mode>" pairs
+// (e.g. "my-cluster=warn,other-cluster=enforce") into a map of cluster names to enforcement modes.
+func ParseClusterEnforcementModes(value string) (map[string]string, error) {
+	modes := map[string]string{}
+	for _, pair := range strings.Split(value, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
 		}
+
+		clusterName, mode, found := strings.Cut(pair, "=")
+		clusterName, mode = strings.TrimSpace(clusterName), strings.TrimSpace(mode)
+		if !found || clusterName == "" || !IsValidEnforcementMode(mode) {
+			return nil, fmt.Errorf("invalid cluster enforcement mode %q, expected <cluster>=%s|%s", pair,
+				common.EnforcementModeEnforce, common.EnforcementModeWarn)
+		}
+		modes[clusterName] = mode
 	}
 
-	return false
+	return modes, nil
+}
+
+// SplitCommaSeparated splits a comma separated list into its trimmed, non-empty entries.
+func SplitCommaSeparated(value string) []string {
+	var entries []string
+	for _, entry := range strings.Split(value, ",") {
+		if entry = strings.TrimSpace(entry); entry != "" {
+			entries = append(entries, entry)
+		}
+	}
+
+	return entries
+}
+
+// enforcementModeFromLabels returns the enforcement mode set by the given namespace labels for the specified
+// clusterName. A cluster specific label takes precedence over the general one, and an empty string is returned
+// when no valid label is set.
+func enforcementModeFromLabels(labels map[string]string, clusterName string) string {
+	if clusterName != "" {
+		if mode := labels[common.EnforcementModeLabel+"-"+clusterName]; IsValidEnforcementMode(mode) {
+			return mode
+		}
+	}
+	if mode := labels[common.EnforcementModeLabel]; IsValidEnforcementMode(mode) {
+		return mode
+	}
+
+	return ""
+}
+
+// ResolveEnforcementMode returns the enforcement mode that applies to objects in the given namespace targeting the
+// given cluster. Namespace labels take precedence over the per cluster modes, which take precedence over the
+// global mode. Enforcement is the default when nothing is configured.
+func ResolveEnforcementMode(ctx context.Context, k8sClient client.Client, namespace, clusterName, globalMode string,
+	clusterModes map[string]string) (string, error) {
+	ns := &corev1.Namespace{}
+	if err := k8sClient.Get(ctx, types.NamespacedName{Name: namespace}, ns); err != nil {
+		return "", fmt.Errorf("client failed to get Namespace %s: %w", namespace, err)
+	}
+
+	if mode := enforcementModeFromLabels(ns.Labels, clusterName); mode != "" {
+		return mode, nil
+	}
+	if mode, ok := clusterModes[clusterName]; ok && clusterName != "" {
+		return mode, nil
+	}
+	if IsValidEnforcementMode(globalMode) {
+		return globalMode, nil
+	}
+
+	return common.EnforcementModeEnforce, nil
+}
+
+// IsInCluster checks if the host of the given value is a known in-cluster value (e.g., "in-cluster",
+// "kubernetes.default.svc.cluster.local") or another service name of the kubernetes Service.
+func IsInCluster(server string) bool {
+	host := serverHost(server)
+	if slices.Contains(common.InClusterValues, host) {
+		return true
+	}
+	labels := strings.Split(host, ".")
+
+	return labels[0] == "kubernetes" && slices.Contains(labels[1:], "svc")
 }
 
 // fetchConfigMapValue retrieves a specific key's value from a ConfigMap in the given namespace.
@@ -206,8 +319,17 @@ func fetchConfigMapValue(
 	return strings.TrimSpace(val), nil
 }
 
-// FetchArgoInstanceName extracts the Application's argocd instance name from the argo-config ConfigMap inside the Application namespace.
+// FetchArgoInstanceName extracts the Application's argocd instance name from the ApplicationRBACPolicy inside the
+// Application namespace, falling back to the argo-config ConfigMap.
 func FetchArgoInstanceName(ctx context.Context, k8sClient client.Client, appNamespace string) (string, error) {
+	policy, err := FetchApplicationRBACPolicy(ctx, k8sClient, appNamespace)
+	if err != nil {
+		return "", err
+	}
+	if policy != nil {
+		return policy.Spec.InstanceName, nil
+	}
+
 	value, err := fetchConfigMapValue(ctx, k8sClient, appNamespace, common.ArgoInstanceConfigMapName, common.ArgoInstanceNameConfigMapKey)
 	if err != nil {
 		return "", err
@@ -216,20 +338,34 @@ func FetchArgoInstanceName(ctx context.Context, k8sClient client.Client, appName
 	return value, nil
 }
 
-// FetchArgoInstanceUsers extracts the Application's admins from the argo-config ConfigMap inside the Application namespace.
-func FetchArgoInstanceUsers(ctx context.Context, k8sClient client.Client, appNamespace string) ([]string, error) {
+// FetchArgoInstanceUsers extracts the Application's admins from the ApplicationRBACPolicy inside the Application
+// namespace, falling back to the argo-config ConfigMap.
+func FetchArgoInstanceUsers(ctx context.Context, k8sClient client.Client, appNamespace string) ([]Subject, error) {
+	policy, err := FetchApplicationRBACPolicy(ctx, k8sClient, appNamespace)
+	if err != nil {
+		return nil, err
+	}
+	if policy != nil {
+		return PolicySubjects(policy), nil
+	}
+
 	value, err := fetchConfigMapValue(ctx, k8sClient, appNamespace, common.ArgoInstanceConfigMapName, common.ArgoInstanceUsersConfigMapKey)
 	if err != nil {
 		return nil, err
 	}
 
-	return strings.Split(value, ","), nil
+	subjects, err := ParseSubjects(value)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", common.ArgoInstanceUsersConfigMapKey, err)
+	}
+
+	return subjects, nil
 }
 
 // FetchClusterToken fetches the token for the destination cluster.
 func FetchClusterToken(ctx context.Context, k8sClient client.Client, appNamespace string, serverURL string) (
 	string, error) {
-	configMapKey := FormatFileSafeServerURL(serverURL) + "-token"
+	configMapKey := FormatFileSafeServerURL(serverURL) + common.ClusterTokenConfigMapKeySuffix
 
 	value, err := fetchConfigMapValue(ctx, k8sClient, appNamespace, common.ClusterTokensConfigMapName, configMapKey)
 	if err != nil {
@@ -263,91 +399,227 @@ func FormatFileSafeServerURL(serverURL string) string {
 	return safeName.String()
 }
 
-// BuildClusterClient creates a kubernetes client for the destination cluster.
-func BuildClusterClient(serverURL, token string) (kubernetes.Interface, error) {
+// BuildClusterRestConfig creates the rest config of the destination cluster. The serving certificate of the cluster
+// is verified against its CA bundle, or the system trust store if it has none, unless the cluster is opted in to
+// insecure access.
+func BuildClusterRestConfig(serverURL string, credentials ClusterCredentials) *rest.Config {
 	config := &rest.Config{
 		Host:        serverURL,
-		BearerToken: token,
-		TLSClientConfig: rest.TLSClientConfig{
-			Insecure: true,
-		},
+		BearerToken: credentials.Token,
+	}
+	config.TLSClientConfig.CertData = credentials.CertData
+	config.TLSClientConfig.KeyData = credentials.KeyData
+	if credentials.Insecure {
+		config.TLSClientConfig.Insecure = true
+	} else {
+		config.TLSClientConfig.CAData = credentials.CAData
 	}
-	return kubernetes.NewForConfig(config)
+	return config
 }
 
-// isNamespaceAdmin checks if the user has admin access to a namespace.
-func isNamespaceAdmin(ctx context.Context, client kubernetes.Interface, user, namespace string) (bool, error) {
-	for _, verb := range common.InstanceUsersAccessLevelVerbs {
-		res, err := client.AuthorizationV1().SubjectAccessReviews().Create(
-			ctx,
-			buildSubjectAccessReview(user, namespace, verb),
-			metav1.CreateOptions{},
-		)
+// BuildClusterClient creates a kubernetes client for the destination cluster.
+func BuildClusterClient(serverURL string, credentials ClusterCredentials) (kubernetes.Interface, error) {
+	return kubernetes.NewForConfig(BuildClusterRestConfig(serverURL, credentials))
+}
 
-		if err != nil {
-			return false, fmt.Errorf("SubjectAccessReview failed: %w", err)
-		}
+// hasAccess checks if the subject is allowed every verb of every rule in a namespace, or cluster-wide if the
+// namespace is empty.
+func hasAccess(ctx context.Context, client kubernetes.Interface, subject Subject, rules []PermissionRule, namespace string) (bool, error) {
+	for _, rule := range rules {
+		for _, verb := range rule.Verbs {
+			res, err := client.AuthorizationV1().SubjectAccessReviews().Create(
+				ctx,
+				buildSubjectAccessReview(subject, namespace, rule, verb),
+				metav1.CreateOptions{},
+			)
+
+			if err != nil {
+				return false, fmt.Errorf("SubjectAccessReview failed: %w", err)
+			}
 
-		if !res.Status.Allowed {
-			return false, nil
+			if !res.Status.Allowed {
+				return false, nil
+			}
 		}
 	}
 
 	return true, nil
 }
 
-// buildSubjectAccessReview creates a SubjectAccessReview.
-func buildSubjectAccessReview(user, namespace, verb string) *authv1.SubjectAccessReview {
-	return &authv1.SubjectAccessReview{
-		Spec: authv1.SubjectAccessReviewSpec{
-			User: user,
-			ResourceAttributes: &authv1.ResourceAttributes{
-				Namespace: namespace,
-				Verb:      verb,
-				Resource:  common.InstanceUsersAccessLevelResource,
-			},
-		},
+// anyAdminHasAccess checks the admins concurrently, with at most common.MaxConcurrentAccessReviews admins being
+// checked at a time, and returns as soon as one of them is allowed every verb of every rule. The reviews of the
+// remaining admins are then canceled. Errors are only reported when no admin is allowed, and an error of the given
+// context is reported when it ends before any admin could be checked.
+func anyAdminHasAccess(ctx context.Context, client kubernetes.Interface, admins []Subject, rules []PermissionRule, namespace string) (bool, error) {
+	reviewCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		allowed  atomic.Bool
+		mu       sync.Mutex
+		firstErr error
+	)
+
+	group := errgroup.Group{}
+	group.SetLimit(common.MaxConcurrentAccessReviews)
+	for _, admin := range admins {
+		group.Go(func() error {
+			if reviewCtx.Err() != nil {
+				return nil
+			}
+
+			isAllowed, err := hasAccess(reviewCtx, client, admin, rules, namespace)
+			if err != nil {
+				mu.Lock()
+				if firstErr == nil {
+					firstErr = fmt.Errorf("error checking access for subject %s: %w", admin, err)
+				}
+				mu.Unlock()
+				return nil
+			}
+
+			if isAllowed {
+				allowed.Store(true)
+				cancel()
+			}
+			return nil
+		})
 	}
+	_ = group.Wait()
+
+	if allowed.Load() {
+		return true, nil
+	}
+	if firstErr == nil && ctx.Err() != nil {
+		return false, fmt.Errorf("access reviews ended before any admin was allowed: %w", ctx.Err())
+	}
+
+	return false, firstErr
 }
 
-// EnsureAnyAdminHasNamespaceAccess verifies that at least one admin has admin-level access to the given namespace in
-// the given cluster.
+// EnsureAnyAdminHasNamespaceAccess verifies that at least one admin has the access the given permission profile
+// requires to the given namespace in the given cluster.
 func EnsureAnyAdminHasNamespaceAccess(
 	ctx context.Context,
 	client kubernetes.Interface,
-	admins []string,
+	admins []Subject,
+	profile PermissionProfile,
 	namespace, cluster string,
 ) error {
-	for _, admin := range admins {
-		isAllowed, err := isNamespaceAdmin(ctx, client, admin, namespace)
-		if err != nil {
-			return fmt.Errorf("error checking access for user %s: %w", admin, err)
-		}
-		if isAllowed {
-			return nil
-		}
+	isAllowed, err := anyAdminHasAccess(ctx, client, admins, profile.Rules, namespace)
+	if err != nil {
+		return decision.Wrap(decision.ReasonDestinationUnreachable, "server", WrapClusterConnectionError(cluster, err)).
+			WithParams(map[string]string{"cluster": cluster})
+	}
+	if isAllowed {
+		return nil
 	}
-	return fmt.Errorf("no users have admin access to namespace %s in cluster %s", namespace, cluster)
+	return decision.New(decision.ReasonDestinationUnauthorized, "namespace",
+		"no users have access to namespace %s in cluster %s as required by permission profile %s",
+		namespace, cluster, profile).
+		WithParams(map[string]string{"namespace": namespace, "cluster": cluster, "profile": profile.Name})
 }
 
-// FetchDestinationClusterSecret retrieves the secret associated with the destination cluster of the given Application.
-func FetchDestinationClusterSecret(ctx context.Context, k8sClient client.Client, app *argoprojv1alpha1.Application) (*corev1.Secret, error) {
+// EnsureAnyAdminHasClusterAccess verifies that at least one admin has the cluster-scoped access the given permission
+// profile requires in the given cluster.
+func EnsureAnyAdminHasClusterAccess(
+	ctx context.Context,
+	client kubernetes.Interface,
+	admins []Subject,
+	profile PermissionProfile,
+	cluster string,
+) error {
+	if len(profile.ClusterRules) == 0 {
+		return decision.New(decision.ReasonInvalidConfiguration, "",
+			"permission profile %s does not define cluster-scoped rules", profile.Name)
+	}
+
+	isAllowed, err := anyAdminHasAccess(ctx, client, admins, profile.ClusterRules, "")
+	if err != nil {
+		return decision.Wrap(decision.ReasonDestinationUnreachable, "server", WrapClusterConnectionError(cluster, err)).
+			WithParams(map[string]string{"cluster": cluster})
+	}
+	if isAllowed {
+		return nil
+	}
+	return decision.New(decision.ReasonClusterScopeUnauthorized, "server",
+		"no users have cluster-scoped access in cluster %s as required by permission profile %s",
+		cluster, profile.ClusterString()).
+		WithParams(map[string]string{"cluster": cluster, "profile": profile.Name})
+}
+
+// FetchDestinationClusterSecret retrieves the secret associated with the destination cluster of the given Application,
+// resolving a short cluster name with the given domain.
+func FetchDestinationClusterSecret(ctx context.Context, k8sClient client.Client, app *argoprojv1alpha1.Application, domain string) (*corev1.Secret, error) {
 
 	destinationServer, err := ResolveDestinationServer(ctx, k8sClient, app)
 	if err != nil {
 		return nil, fmt.Errorf("failed to resolve destination server: %w", err)
 	}
 
-	destinationURl, err := url.Parse(destinationServer)
-	if err != nil {
-		return nil, fmt.Errorf("failed to parse destination server URL %s: %w", destinationServer, err)
+	return FetchClusterSecret(ctx, k8sClient, app.Namespace, destinationServer, domain)
+}
+
+// FetchClusterSecret retrieves the secret associated with the given destination server inside the given namespace.
+// A short cluster name is resolved with the given domain. Without a domain, the secret of a short cluster name is the
+// only one whose destination key starts with that name, and an error is returned if several secrets do.
+func FetchClusterSecret(ctx context.Context, k8sClient client.Client, namespace, destinationServer, domain string) (*corev1.Secret, error) {
+	destination := DestinationKey(destinationServer, domain)
+	if !IsClusterNameKey(destination) {
+		secretName, err := ClusterSecretName(destinationServer, domain)
+		if err != nil {
+			return nil, err
+		}
+
+		secret := &corev1.Secret{}
+		err = k8sClient.Get(ctx, types.NamespacedName{Name: secretName, Namespace: namespace}, secret)
+		return secret, err
+	}
+
+	secretList := &corev1.SecretList{}
+	if err := k8sClient.List(ctx, secretList, client.InNamespace(namespace)); err != nil {
+		return nil, fmt.Errorf("failed to list secrets in namespace %s: %w", namespace, err)
+	}
+
+	var found *corev1.Secret
+	for i := range secretList.Items {
+		secretDestination, ok := strings.CutSuffix(secretList.Items[i].Name, "-"+common.SecretNameSuffix)
+		if !ok || strings.Split(secretDestination, ".")[0] != destination {
+			continue
+		}
+		if found != nil {
+			return nil, fmt.Errorf("cluster name %q matches both cluster secrets %s and %s, set %s to resolve it",
+				destination, found.Name, secretList.Items[i].Name, common.ClusterDomainEnvVarKey)
+		}
+		found = &secretList.Items[i]
 	}
+	if found == nil {
+		return nil, errors.NewNotFound(corev1.Resource("secrets"), fmt.Sprintf("%s-%s", destination, common.SecretNameSuffix))
+	}
+
+	return found, nil
+}
+
+// ClusterSecretName returns the name of the cluster secret of the given destination server, which is its
+// destination key followed by the cluster secret suffix. It cannot be derived from a short cluster name without a
+// domain.
+func ClusterSecretName(destinationServer, domain string) (string, error) {
+	if _, err := url.Parse(destinationServer); err != nil {
+		return "", fmt.Errorf("failed to parse destination server URL %s: %w", destinationServer, err)
+	}
+
+	destination := DestinationKey(destinationServer, domain)
+	if destination == "" || IsClusterNameKey(destination) {
+		return "", fmt.Errorf("cannot derive the cluster secret name of destination server %q", destinationServer)
+	}
+	return fmt.Sprintf("%s-%s", destination, common.SecretNameSuffix), nil
+}
 
-	destination := strings.TrimPrefix(destinationURl.Hostname(), "api.")
-	secretName := fmt.Sprintf("%s-%s", destination, common.SecretNameSuffix)
-	secret := &corev1.Secret{}
-	err = k8sClient.Get(ctx, types.NamespacedName{Name: secretName, Namespace: app.Namespace}, secret)
-	return secret, err
+// IsClusterSecretOf checks whether the cluster secret with the given name is the one of the given destination server,
+// resolving a short cluster name with the given domain.
+func IsClusterSecretOf(secretName, destinationServer, domain string) bool {
+	destination, ok := strings.CutSuffix(secretName, "-"+common.SecretNameSuffix)
+	return ok && !IsInCluster(destinationServer) && destination == DestinationKey(destinationServer, domain)
 }
 
 // ExtractNamespacesFromSecret extracts the list of namespaces from the cluster secret's data.
@@ -360,20 +632,38 @@ func ExtractNamespacesFromSecret(secret *corev1.Secret) []string {
 
 }
 
-// IsDestinationNamespaceInUse checks if any other application is deploying to the same namespace in the same cluster.
-func IsDestinationNamespaceInUse(applicationList *argoprojv1alpha1.ApplicationList, app *argoprojv1alpha1.Application, destinationNS string) bool {
-	for _, otherApp := range applicationList.Items {
-		if otherApp.Name != app.Name && otherApp.Spec.Destination.Namespace == destinationNS && otherApp.Spec.Destination.Server == app.Spec.Destination.Server {
-			return true
+// IsDestinationNamespaceInUse checks if any other application is deploying to the same namespace in the same cluster,
+// whatever form their destinations are written in, resolving short cluster names with the given domain. Another
+// application deploying to the same namespace whose destination cannot be resolved is conservatively treated as using
+// it.
+func IsDestinationNamespaceInUse(ctx context.Context, k8sClient client.Client, applicationList *argoprojv1alpha1.ApplicationList,
+	app *argoprojv1alpha1.Application, destinationNS, domain string) (bool, error) {
+	destinationKey, err := ResolveDestinationKey(ctx, k8sClient, app, domain)
+	if err != nil {
+		return false, err
+	}
+
+	for i := range applicationList.Items {
+		otherApp := &applicationList.Items[i]
+		if otherApp.Name == app.Name || otherApp.Spec.Destination.Namespace != destinationNS {
+			continue
+		}
+		otherKey, err := ResolveDestinationKey(ctx, k8sClient, otherApp, domain)
+		if err != nil || otherKey == destinationKey {
+			return true, nil
 		}
 	}
-	return false
+	return false, nil
 }
 
 // RetryUpdateSecret retries updating the secret with the new namespace list in case of a conflict.
-func RetryUpdateSecret(ctx context.Context, k8sClient client.Client, app *argoprojv1alpha1.Application, namespaceList []string) error {
+func RetryUpdateSecret(ctx context.Context, k8sClient client.Client, app *argoprojv1alpha1.Application, namespaceList []string, domain string) (err error) {
+	ctx, span := tracing.Start(ctx, "RetryUpdateSecret", attribute.String("namespace", app.Namespace),
+		attribute.String("name", app.Name))
+	defer func() { tracing.End(span, err) }()
+
 	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
-		secret, err := FetchDestinationClusterSecret(ctx, k8sClient, app)
+		secret, err := FetchDestinationClusterSecret(ctx, k8sClient, app, domain)
 		if err != nil {
 			return err
 		}
@@ -386,6 +676,27 @@ func RetryUpdateSecret(ctx context.Context, k8sClient client.Client, app *argopr
 	})
 }
 
+// RetryUpdateSecretNamespaces retries updating the cluster secret with the given key with the new namespace list in
+// case of a conflict.
+func RetryUpdateSecretNamespaces(ctx context.Context, k8sClient client.Client, key types.NamespacedName, namespaceList []string) (err error) {
+	ctx, span := tracing.Start(ctx, "RetryUpdateSecretNamespaces", attribute.String("namespace", key.Namespace),
+		attribute.String("name", key.Name))
+	defer func() { tracing.End(span, err) }()
+
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		secret := &corev1.Secret{}
+		if err := k8sClient.Get(ctx, key, secret); err != nil {
+			return err
+		}
+		if secret.Data == nil {
+			secret.Data = map[string][]byte{}
+		}
+		secret.Data[common.NamespaceKey] = []byte(strings.Join(namespaceList, ","))
+
+		return k8sClient.Update(ctx, secret)
+	})
+}
+
 // ShouldBypassOptimization checks if the secret has the bypass optimization key set to "true".
 func ShouldBypassOptimization(secret *corev1.Secret) bool {
 	if secret.Labels == nil {
@@ -3,6 +3,7 @@ package utils
 import (
 	"context"
 	"os"
+	"slices"
 	"strings"
 	"testing"
 
@@ -24,6 +25,7 @@ const (
 	inClusterServerURL        = "kubernetes.svc.cluster.local"
 	sampleFQDNServerURL       = "https://api.my-cluster.domain.example.com:6443"
 	sampleClusterServerURL    = "https://api.my-cluster.example.com:6443"
+	sampleServerUrlDomain     = "example.com"
 	sampleArgoInstanceName    = "argo-instance"
 	sampleUser                = "user1"
 	sampleArgoCDNamespace     = "argocd"
@@ -88,6 +90,62 @@ func TestIsNotSpecUpdate(t *testing.T) {
 	}
 }
 
+func TestIsAutoSyncDisableUpdate(t *testing.T) {
+	enabled, disabled := true, false
+	newApp := func(namespace string, automated *argoprojv1alpha1.SyncPolicyAutomated) *argoprojv1alpha1.Application {
+		return &argoprojv1alpha1.Application{
+			Spec: argoprojv1alpha1.ApplicationSpec{
+				Destination: argoprojv1alpha1.ApplicationDestination{
+					Server:    sampleServerName,
+					Namespace: namespace,
+				},
+				SyncPolicy: &argoprojv1alpha1.SyncPolicy{Automated: automated},
+			},
+		}
+	}
+
+	testCases := []struct {
+		name     string
+		oldApp   *argoprojv1alpha1.Application
+		newApp   *argoprojv1alpha1.Application
+		expected bool
+	}{
+		{
+			name:     "should return true when only automated sync is disabled",
+			oldApp:   newApp(sampleNamespaceName, &argoprojv1alpha1.SyncPolicyAutomated{Prune: true}),
+			newApp:   newApp(sampleNamespaceName, &argoprojv1alpha1.SyncPolicyAutomated{Prune: true, Enabled: &disabled}),
+			expected: true,
+		},
+		{
+			name:     "should return false when automated sync is enabled",
+			oldApp:   newApp(sampleNamespaceName, &argoprojv1alpha1.SyncPolicyAutomated{Enabled: &disabled}),
+			newApp:   newApp(sampleNamespaceName, &argoprojv1alpha1.SyncPolicyAutomated{Enabled: &enabled}),
+			expected: false,
+		},
+		{
+			name:     "should return false when the destination changes too",
+			oldApp:   newApp(sampleNamespaceName, &argoprojv1alpha1.SyncPolicyAutomated{}),
+			newApp:   newApp("different-namespace", &argoprojv1alpha1.SyncPolicyAutomated{Enabled: &disabled}),
+			expected: false,
+		},
+		{
+			name:     "should return false when automated sync is added disabled",
+			oldApp:   newApp(sampleNamespaceName, nil),
+			newApp:   newApp(sampleNamespaceName, &argoprojv1alpha1.SyncPolicyAutomated{Enabled: &disabled}),
+			expected: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			result := IsAutoSyncDisableUpdate(tc.oldApp, tc.newApp)
+			if result != tc.expected {
+				t.Errorf("expected %v but got %v", tc.expected, result)
+			}
+		})
+	}
+}
+
 func TestValidateServerUrlFormat(t *testing.T) {
 	testCases := []struct {
 		name     string
@@ -623,6 +681,33 @@ func TestFetchArgoInstanceUsers(t *testing.T) {
 			expectError: false,
 			expected:    []string{sampleUser},
 		},
+		{
+			name: "should fetch typed subjects",
+			configMap: &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      common.ArgoInstanceConfigMapName,
+					Namespace: sampleNamespaceName,
+				},
+				Data: map[string]string{
+					common.ArgoInstanceUsersConfigMapKey: "user1, group:platform-admins,serviceaccount:ci/deployer,",
+				},
+			},
+			expectError: false,
+			expected:    []string{sampleUser, "group:platform-admins", "serviceaccount:ci/deployer"},
+		},
+		{
+			name: "should return error for a malformed subject",
+			configMap: &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      common.ArgoInstanceConfigMapName,
+					Namespace: sampleNamespaceName,
+				},
+				Data: map[string]string{
+					common.ArgoInstanceUsersConfigMapKey: "user1,group:",
+				},
+			},
+			expectError: true,
+		},
 		{
 			name: "should return error when key missing",
 			configMap: &corev1.ConfigMap{
@@ -657,7 +742,7 @@ func TestFetchArgoInstanceUsers(t *testing.T) {
 					t.Errorf("expected length %d but got %d", len(tc.expected), len(result))
 				}
 				for i, user := range tc.expected {
-					if result[i] != user {
+					if result[i].String() != user {
 						t.Errorf("expected user at index %d to be %s but got %s", i, user, result[i])
 					}
 				}
@@ -845,3 +930,182 @@ func TestResolveDestinationServer(t *testing.T) {
 		})
 	}
 }
+
+func TestIsGlobPattern(t *testing.T) {
+	testCases := []struct {
+		name     string
+		value    string
+		expected bool
+	}{
+		{
+			name:     "should return true for a wildcard",
+			value:    "*",
+			expected: true,
+		},
+		{
+			name:     "should return true for a partial glob",
+			value:    "team-?-[ab]",
+			expected: true,
+		},
+		{
+			name:     "should return false for a concrete value",
+			value:    sampleClusterServerURL,
+			expected: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			result := IsGlobPattern(tc.value)
+			if result != tc.expected {
+				t.Errorf("expected %v but got %v", tc.expected, result)
+			}
+		})
+	}
+}
+
+func TestSplitCommaSeparated(t *testing.T) {
+	testCases := []struct {
+		name     string
+		value    string
+		expected []string
+	}{
+		{
+			name:     "should return nothing for an empty value",
+			value:    "",
+			expected: nil,
+		},
+		{
+			name:     "should trim entries and skip empty ones",
+			value:    " https://kubernetes.default.svc, ,application-rbac-validator ",
+			expected: []string{"https://kubernetes.default.svc", "application-rbac-validator"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			result := SplitCommaSeparated(tc.value)
+			if !slices.Equal(result, tc.expected) {
+				t.Errorf("expected %v but got %v", tc.expected, result)
+			}
+		})
+	}
+}
+
+func TestParseClusterEnforcementModes(t *testing.T) {
+	testCases := []struct {
+		name        string
+		value       string
+		expected    map[string]string
+		expectError bool
+	}{
+		{
+			name:     "should return an empty map for an empty value",
+			value:    "",
+			expected: map[string]string{},
+		},
+		{
+			name:  "should parse multiple clusters",
+			value: "my-cluster=warn, other-cluster=enforce",
+			expected: map[string]string{
+				sampleClusterName: common.EnforcementModeWarn,
+				"other-cluster":   common.EnforcementModeEnforce,
+			},
+		},
+		{
+			name:        "should return error for an unknown mode",
+			value:       "my-cluster=audit",
+			expectError: true,
+		},
+		{
+			name:        "should return error for a missing mode",
+			value:       sampleClusterName,
+			expectError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := ParseClusterEnforcementModes(tc.value)
+			if tc.expectError && err == nil {
+				t.Errorf("expected error but got none")
+			}
+			if !tc.expectError && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if !tc.expectError && len(result) != len(tc.expected) {
+				t.Errorf("expected %v but got %v", tc.expected, result)
+			}
+			for cluster, mode := range tc.expected {
+				if result[cluster] != mode {
+					t.Errorf("expected mode %q for cluster %q but got %q", mode, cluster, result[cluster])
+				}
+			}
+		})
+	}
+}
+
+func TestResolveEnforcementMode(t *testing.T) {
+	testCases := []struct {
+		name         string
+		labels       map[string]string
+		clusterModes map[string]string
+		globalMode   string
+		expected     string
+	}{
+		{
+			name:     "should default to enforce",
+			expected: common.EnforcementModeEnforce,
+		},
+		{
+			name:       "should use the global mode",
+			globalMode: common.EnforcementModeWarn,
+			expected:   common.EnforcementModeWarn,
+		},
+		{
+			name:         "should prefer the cluster mode over the global mode",
+			clusterModes: map[string]string{sampleClusterName: common.EnforcementModeEnforce},
+			globalMode:   common.EnforcementModeWarn,
+			expected:     common.EnforcementModeEnforce,
+		},
+		{
+			name:         "should prefer the namespace label over the cluster mode",
+			labels:       map[string]string{common.EnforcementModeLabel: common.EnforcementModeWarn},
+			clusterModes: map[string]string{sampleClusterName: common.EnforcementModeEnforce},
+			expected:     common.EnforcementModeWarn,
+		},
+		{
+			name: "should prefer the cluster specific namespace label over the general one",
+			labels: map[string]string{
+				common.EnforcementModeLabel:                           common.EnforcementModeWarn,
+				common.EnforcementModeLabel + "-" + sampleClusterName: common.EnforcementModeEnforce,
+			},
+			expected: common.EnforcementModeEnforce,
+		},
+		{
+			name:     "should ignore invalid label values",
+			labels:   map[string]string{common.EnforcementModeLabel: "audit"},
+			expected: common.EnforcementModeEnforce,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cl := testutils.NewFakeClient(&corev1.Namespace{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:   sampleNamespaceObjectName,
+					Labels: tc.labels,
+				},
+			})
+
+			result, err := ResolveEnforcementMode(context.Background(), cl, sampleNamespaceObjectName, sampleClusterName,
+				tc.globalMode, tc.clusterModes)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result != tc.expected {
+				t.Errorf("expected %v but got %v", tc.expected, result)
+			}
+		})
+	}
+}
@@ -19,13 +19,21 @@ package v1alpha1
 import (
 	"context"
 	"fmt"
+	"time"
 
 	argoprojv1alpha1 "github.com/argoproj/argo-cd/v3/pkg/apis/application/v1alpha1"
+	rbacv1alpha1 "github.com/dana-team/application-rbac-validator/api/v1alpha1"
+	"github.com/dana-team/application-rbac-validator/internal/audit"
 	"github.com/dana-team/application-rbac-validator/internal/common"
+	"github.com/dana-team/application-rbac-validator/internal/decision"
 	"github.com/dana-team/application-rbac-validator/internal/handlers"
+	"github.com/dana-team/application-rbac-validator/internal/metrics"
+	"github.com/dana-team/application-rbac-validator/internal/tracing"
 	"github.com/dana-team/application-rbac-validator/internal/utils"
+	"go.opentelemetry.io/otel/attribute"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
-	"k8s.io/client-go/kubernetes"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
@@ -33,9 +41,9 @@ import (
 )
 
 // SetupApplicationWebhookWithManager registers the webhook for Application in the manager.
-func SetupApplicationWebhookWithManager(mgr ctrl.Manager, serverUrlDomain string) error {
+func SetupApplicationWebhookWithManager(mgr ctrl.Manager, config ValidatorConfig) error {
 	return ctrl.NewWebhookManagedBy(mgr).For(&argoprojv1alpha1.Application{}).
-		WithValidator(&ApplicationCustomValidator{Client: mgr.GetClient(), ServerUrlDomain: serverUrlDomain}).
+		WithValidator(&ApplicationCustomValidator{Client: mgr.GetClient(), ValidatorConfig: config.withManagerRecorder(mgr)}).
 		Complete()
 }
 
@@ -43,9 +51,8 @@ func SetupApplicationWebhookWithManager(mgr ctrl.Manager, serverUrlDomain string
 // +kubebuilder:webhook:path=/validate-argoproj-io-v1alpha1-application,mutating=false,failurePolicy=fail,sideEffects=None,groups=argoproj.io,resources=applications,verbs=create;update,versions=v1alpha1,name=vapplication-v1alpha1.kb.io,admissionReviewVersions=v1
 
 type ApplicationCustomValidator struct {
-	Client                   client.Client
-	destinationClusterClient kubernetes.Interface
-	ServerUrlDomain          string
+	ValidatorConfig
+	Client client.Client
 }
 
 // ValidateCreate implements webhook.CustomValidator so a webhook will be registered for the type Application.
@@ -57,7 +64,11 @@ func (v *ApplicationCustomValidator) ValidateCreate(ctx context.Context, obj run
 	}
 	logger.Info("Validation for Application upon creation", "name", application.GetName())
 
-	return nil, validateApplication(ctx, v.Client, v.destinationClusterClient, application, v.ServerUrlDomain)
+	ctx = newAuditContext(ctx, "Application", application, application.Spec.Destination)
+
+	resolved := &resolution{}
+	err := validateApplication(ctx, v.Client, application, application, v.ValidatorConfig, resolved)
+	return v.admit(ctx, v.Client, "Application", application, resolved, err)
 }
 
 // ValidateUpdate implements webhook.CustomValidator so a webhook will be registered for the type Application.
@@ -76,10 +87,20 @@ func (v *ApplicationCustomValidator) ValidateUpdate(ctx context.Context, oldObj,
 
 	if utils.IsNotSpecUpdate(oldApplication, newApplication) {
 		logger.V(-1).Info("Only a status update, approving automatically.")
+		v.approve(ctx, "Application", newApplication, metrics.ReasonNotSpecUpdate, newApplication.Spec.Destination)
+		return nil, nil
+	}
+	if utils.IsAutoSyncDisableUpdate(oldApplication, newApplication) {
+		logger.Info("Only disabling automated sync, approving automatically.")
+		v.approve(ctx, "Application", newApplication, metrics.ReasonAutoSyncDisabled, newApplication.Spec.Destination)
 		return nil, nil
 	}
 
-	return nil, validateApplication(ctx, v.Client, v.destinationClusterClient, newApplication, v.ServerUrlDomain)
+	ctx = newAuditContext(ctx, "Application", newApplication, newApplication.Spec.Destination)
+
+	resolved := &resolution{}
+	err := validateApplication(ctx, v.Client, newApplication, newApplication, v.ValidatorConfig, resolved)
+	return v.admit(ctx, v.Client, "Application", newApplication, resolved, err)
 }
 
 // ValidateDelete triggers a cleanup of the application destination secret.
@@ -90,61 +111,142 @@ func (v *ApplicationCustomValidator) ValidateDelete(ctx context.Context, obj run
 		return nil, fmt.Errorf("expected a Application object but got %T", obj)
 	}
 	log.Info("Cleaning up", "name", application.GetName())
-	return nil, handlers.HandleDelete(log, ctx, v.Client, application)
+	return nil, handlers.HandleDelete(log, ctx, v.Client, v.recorder(), application, v.ServerUrlDomain)
 }
 
-// validateApplication prevents unauthorized application deployments across clusters or namespaces.
-func validateApplication(ctx context.Context, k8sClient client.Client, destinationClusterClient kubernetes.Interface, application *argoprojv1alpha1.Application, serverUrlDomain string) error {
+// ValidateApplication validates the given Application outside of an admission request, so existing Applications
+// can be re-validated. Skipped validations are not recorded as Events.
+func ValidateApplication(ctx context.Context, k8sClient client.Client, application *argoprojv1alpha1.Application, config ValidatorConfig) error {
+	config.Recorder = nil
+	return validateApplication(ctx, k8sClient, application, application, config, &resolution{})
+}
+
+// validateApplication prevents unauthorized application deployments across clusters or namespaces. The fields of
+// its denials are relative to the Application, Events about skipped validations are recorded on the given subject,
+// and its destination cluster and Argo instance are recorded in the given resolution.
+func validateApplication(ctx context.Context, k8sClient client.Client, application *argoprojv1alpha1.Application, subject client.Object,
+	config ValidatorConfig, resolved *resolution) (err error) {
+	ctx, span := tracing.Start(ctx, "validateApplication", attribute.String("namespace", application.Namespace),
+		attribute.String("name", application.Name))
+	defer func() { tracing.End(span, err) }()
+	defer func() { err = decision.WithFieldPrefix(err, "spec.destination") }()
 
 	logger := zap.New().WithName("webhook")
 	destNamespace := application.Spec.Destination.Namespace
 	appNamespace := application.GetNamespace()
 
-	if destNamespace == "" || (application.Spec.Destination.Server == "" && application.Spec.Destination.Name == "") {
-		return fmt.Errorf("destination namespace and (server or name) must be specified")
+	if (destNamespace == "" && !config.ClusterScopedValidation) || (application.Spec.Destination.Server == "" && application.Spec.Destination.Name == "") {
+		return decision.New(decision.ReasonDestinationMissing, missingDestinationField(application.Spec.Destination),
+			"destination namespace and (server or name) must be specified")
 	}
 
-	destServer, err := utils.ResolveDestinationServer(ctx, k8sClient, application)
+	resolveCtx, resolveStage := startStage(ctx, metrics.StageServerResolution)
+	destServer, err := utils.ResolveDestinationServer(resolveCtx, k8sClient, application)
+	resolveStage.end(err)
 	if err != nil {
-		return fmt.Errorf("failed to resolve destination server: %w", err)
+		return decision.Wrap(decision.ReasonDestinationUnresolved, destinationServerField(application.Spec.Destination),
+			fmt.Errorf("failed to resolve destination server: %w", err))
 	}
 
+	resolved.cluster = utils.ExtractClusterName(destServer)
 	logger = logger.WithValues(
 		"destinationServer", destServer,
 	)
 
-	if serverUrlDomain == "" {
+	if config.ServerUrlDomain == "" {
 		logger.Info(fmt.Sprintf("Failed to fetch environment variable %s, validation might fail if server is not a URL", common.ClusterDomainEnvVarKey))
 	}
 
 	logger.Info("Checking if bypass label exists on the Application's namespace")
-	isBypassLabelExists, err := utils.BypassLabelExists(ctx, k8sClient, appNamespace, utils.ExtractClusterName(destServer))
+	bypassCtx, bypassStage := startStage(ctx, metrics.StageBypassLookup)
+	isBypassLabelExists, err := utils.BypassLabelExists(bypassCtx, k8sClient, appNamespace, utils.ExtractClusterName(destServer))
 	if err != nil {
+		bypassStage.end(err)
 		return fmt.Errorf("failed to check bypass label on the Application's namespace: %w", err)
 	}
 	if isBypassLabelExists {
+		bypassStage.end(nil)
 		logger.Info("Application approved")
+		audit.FromContext(ctx).SetBypass(audit.BypassLabel, "")
+		config.recorder().Eventf(subject, corev1.EventTypeNormal, ReasonBypassedByLabel,
+			"Application %s targeting namespace %q in cluster %s approved by the bypass label of namespace %s",
+			application.Name, destNamespace, destServer, appNamespace)
+		return nil
+	}
+
+	logger.Info("Checking if a bypass grant covers the Application's destination")
+	grant, err := utils.FindActiveBypassGrant(bypassCtx, k8sClient, appNamespace, utils.ExtractClusterName(destServer), destNamespace, time.Now())
+	bypassStage.end(err)
+	if err != nil {
+		return fmt.Errorf("failed to check bypass grants in the Application's namespace: %w", err)
+	}
+	if grant != nil {
+		logger.Info("Application approved by bypass grant", "bypassGrant", grant.Name, "reason", grant.Spec.Reason,
+			"approver", grant.Spec.Approver, "expiresAt", grant.Spec.ExpiresAt)
+		audit.FromContext(ctx).SetBypass(audit.BypassGrant, grant.Name)
+		config.recorder().Eventf(subject, corev1.EventTypeNormal, ReasonBypassedByGrant,
+			"Application %s targeting namespace %q in cluster %s approved by BypassGrant %s (approver %s, expires at %s)",
+			application.Name, destNamespace, destServer, grant.Name, grant.Spec.Approver, grant.Spec.ExpiresAt.UTC().Format(time.RFC3339))
 		return nil
 	}
 
 	logger.Info("Checking if its a management Application")
 
-	argoInstanceName, err := utils.FetchArgoInstanceName(ctx, k8sClient, appNamespace)
+	configCtx, configStage := startStage(ctx, metrics.StageConfigLookup)
+	argoInstanceName, err := utils.FetchArgoInstanceName(configCtx, k8sClient, appNamespace)
 	if err != nil {
-		return fmt.Errorf("failed to fetch Application's argo instance name: %w", err)
+		configStage.end(err)
+		return decision.Wrap(decision.ReasonInvalidConfiguration, "",
+			fmt.Errorf("failed to fetch Application's argo instance name: %w", err))
 	}
+	resolved.instance = argoInstanceName
 
-	isManagementApplication := utils.IsManagementApplication(argoInstanceName, application.Name)
+	policy, err := utils.FetchApplicationRBACPolicy(configCtx, k8sClient, appNamespace)
+	configStage.end(err)
+	if err != nil {
+		return decision.Wrap(decision.ReasonInvalidConfiguration, "",
+			fmt.Errorf("failed to fetch Application's ApplicationRBACPolicy: %w", err))
+	}
 
-	if isManagementApplication {
-		logger.Info("Application approved")
-		return nil
+	managementMatcher, err := utils.NewManagementMatcher(policy, argoInstanceName)
+	if err != nil {
+		return decision.Wrap(decision.ReasonInvalidConfiguration, "",
+			fmt.Errorf("failed to build the management Application matcher: %w", err))
 	}
 
+	if managementMatcher.Matches(application) {
+		if managementMatcher.IsExemptDestination(destServer, destNamespace) {
+			logger.Info("Application approved")
+			audit.FromContext(ctx).SetBypass(audit.BypassManagement, "")
+			config.recorder().Eventf(subject, corev1.EventTypeNormal, ReasonManagementApplication,
+				"Application %s targeting namespace %q in cluster %s approved as a management Application of Argo instance %s",
+				application.Name, destNamespace, destServer, argoInstanceName)
+			return nil
+		}
+		logger.Info("Management Application targets a destination it does not manage, validating it")
+	}
+
+	if err := validateDestinationAccess(ctx, k8sClient, appNamespace, destServer, destNamespace, policy, config); err != nil {
+		return err
+	}
+
+	logger.Info("Application approved")
+
+	return nil
+}
+
+// validateDestinationAccess ensures that the destination is not the current cluster and that at least one of the
+// argo instance admins has admin access to the destination namespace on the destination cluster. When the
+// Application's namespace holds an ApplicationRBACPolicy, the destination cluster must also be allowed by it.
+func validateDestinationAccess(ctx context.Context, k8sClient client.Client, appNamespace, destServer, destNamespace string,
+	policy *rbacv1alpha1.ApplicationRBACPolicy, config ValidatorConfig) error {
+	logger := zap.New().WithName("webhook").WithValues("destinationServer", destServer)
+
 	logger.Info("Ensuring the Application's server and the destination server are not the same")
 
 	if utils.IsInCluster(destServer) {
-		return fmt.Errorf("destination server must not be the same as the Application's current cluster")
+		return decision.New(decision.ReasonInClusterForbidden, "server",
+			"destination server must not be the same as the Application's current cluster")
 	}
 
 	logger.Info("Fetching the webhook's current namespace name")
@@ -157,39 +259,119 @@ func validateApplication(ctx context.Context, k8sClient client.Client, destinati
 	logger.Info("Building destination Server url")
 
 	if !utils.ValidateServerUrlFormat(destServer) {
-		destServer = utils.BuildServerUrl(destServer, serverUrlDomain)
+		destServer = utils.BuildServerUrl(utils.ExtractClusterName(destServer), config.ServerUrlDomain)
 	}
 
-	logger.Info("Fetching destination cluster token")
+	if !utils.IsClusterAllowed(policy, destServer) {
+		return decision.New(decision.ReasonClusterNotAllowed, "server",
+			"destination cluster %s is not allowed by ApplicationRBACPolicy %s", destServer, policy.Name).
+			WithParams(map[string]string{"policy": policy.Name, "cluster": utils.ExtractClusterName(destServer)})
+	}
+
+	logger.Info("Fetching destination cluster credentials")
 
-	token, err := utils.FetchClusterToken(ctx, k8sClient, currentNamespace, destServer)
+	tokenCtx, tokenStage := startStage(ctx, metrics.StageTokenFetch)
+	credentials, err := utils.FetchClusterCredentials(tokenCtx, k8sClient, config.ClusterSecretsNamespace, currentNamespace, destServer)
+	tokenStage.end(err)
 	if err != nil {
-		return fmt.Errorf("failed to fetch cluster token: %w", err)
+		return decision.Wrap(decision.ReasonTokenMissing, "server", fmt.Errorf("failed to fetch cluster credentials: %w", err)).
+			WithParams(map[string]string{"server": destServer})
+	}
+	if credentials.Insecure {
+		logger.Info("Destination cluster is opted in to insecure access, skipping TLS verification")
 	}
 
 	logger.Info("Accessing destination cluster")
 
-	if destinationClusterClient == nil {
-		destinationClusterClient, err = utils.BuildClusterClient(destServer, token)
-		if err != nil {
-			return fmt.Errorf("failed to build destination's cluster client: %w", err)
-		}
+	_, clientStage := startStage(ctx, metrics.StageClientBuild)
+	destinationClusterClient, err := config.clusterClients().ClientFor(destServer, credentials)
+	clientStage.end(err)
+	if err != nil {
+		return decision.Wrap(decision.ReasonDestinationUnreachable, "server",
+			fmt.Errorf("failed to build destination's cluster client: %w", err)).
+			WithParams(map[string]string{"cluster": utils.ExtractClusterName(destServer)})
 	}
 
 	logger.Info("Fetching authorized administrators for the Application's target environment.")
 
-	admins, err := utils.FetchArgoInstanceUsers(ctx, k8sClient, appNamespace)
+	configCtx, configStage := startStage(ctx, metrics.StageConfigLookup)
+	admins, err := utils.FetchArgoInstanceUsers(configCtx, k8sClient, appNamespace)
 	if err != nil {
+		configStage.end(err)
 		return fmt.Errorf("failed to fetch Application's admins: %w", err)
 	}
 
-	logger.Info("Validating namespace access for account", "account", admins, "namespace", destNamespace, "cluster", destServer)
+	profile, err := utils.ResolvePermissionProfile(configCtx, k8sClient, currentNamespace, appNamespace, utils.ExtractClusterName(destServer))
+	configStage.end(err)
+	if err != nil {
+		return decision.Wrap(decision.ReasonInvalidConfiguration, "", fmt.Errorf("failed to resolve permission profile: %w", err))
+	}
+
+	if destNamespace != "" {
+		logger.Info("Validating namespace access for account", "account", admins, "namespace", destNamespace, "cluster", destServer,
+			"profile", profile.Name)
+
+		reviewCtx, reviewStage := startStage(ctx, metrics.StageAccessReview)
+		err := utils.EnsureAnyAdminHasNamespaceAccess(reviewCtx, destinationClusterClient, admins, profile, destNamespace, destServer)
+		reviewStage.end(err)
+		if err != nil {
+			return err
+		}
+	}
 
-	if err := utils.EnsureAnyAdminHasNamespaceAccess(ctx, destinationClusterClient, admins, destNamespace, destServer); err != nil {
+	isClusterScoped, err := isClusterScopedDestination(ctx, k8sClient, appNamespace, destServer, destNamespace, config)
+	if err != nil {
 		return err
 	}
+	if !isClusterScoped {
+		return nil
+	}
 
-	logger.Info("Application approved")
+	logger.Info("Validating cluster-scoped access for account", "account", admins, "cluster", destServer, "profile", profile.Name)
 
-	return nil
+	reviewCtx, reviewStage := startStage(ctx, metrics.StageAccessReview)
+	err = utils.EnsureAnyAdminHasClusterAccess(reviewCtx, destinationClusterClient, admins, profile, destServer)
+	reviewStage.end(err)
+
+	return err
+}
+
+// isClusterScopedDestination returns a bool indicating whether cluster-scoped access has to be validated for the
+// destination, which is the case when cluster-scoped validation is enabled and either the destination has no
+// namespace or the destination cluster's secret allows cluster resources.
+func isClusterScopedDestination(ctx context.Context, k8sClient client.Client, appNamespace, destServer, destNamespace string, config ValidatorConfig) (bool, error) {
+	if !config.ClusterScopedValidation {
+		return false, nil
+	}
+	if destNamespace == "" {
+		return true, nil
+	}
+
+	secret, err := utils.FetchClusterSecret(ctx, k8sClient, appNamespace, destServer, config.ServerUrlDomain)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to fetch destination cluster secret: %w", err)
+	}
+
+	return handlers.IsClusterWide(secret), nil
+}
+
+// missingDestinationField returns the field of the given destination that is missing for it to be validated.
+func missingDestinationField(destination argoprojv1alpha1.ApplicationDestination) string {
+	if destination.Server == "" && destination.Name == "" {
+		return destinationServerField(destination)
+	}
+
+	return "namespace"
+}
+
+// destinationServerField returns the field the given destination references its cluster by.
+func destinationServerField(destination argoprojv1alpha1.ApplicationDestination) string {
+	if destination.Server == "" && destination.Name != "" {
+		return "name"
+	}
+
+	return "server"
 }
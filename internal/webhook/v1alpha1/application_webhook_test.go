@@ -22,6 +22,7 @@ import (
 	"os"
 
 	argoprojv1alpha1 "github.com/argoproj/argo-cd/v3/pkg/apis/application/v1alpha1"
+	"github.com/dana-team/application-rbac-validator/internal/clusterclient"
 	"github.com/dana-team/application-rbac-validator/internal/common"
 	"github.com/dana-team/application-rbac-validator/internal/utils"
 	testutils "github.com/dana-team/application-rbac-validator/test/utils"
@@ -48,8 +49,8 @@ var _ = Describe("application-rbac-validator Webhook", func() {
 
 		BeforeEach(func() {
 			testValidator = ApplicationCustomValidator{Client: k8sClient,
-				destinationClusterClient: testutils.NewMockedDestinationClusterClient(),
-				ServerUrlDomain:          "example.com"}
+				ValidatorConfig: ValidatorConfig{ServerUrlDomain: "example.com",
+					ClusterClients: clusterclient.NewStaticProvider(testutils.NewMockedDestinationClusterClient())}}
 			Expect(testValidator).NotTo(BeNil(), "Expected validator to be initialized")
 
 			resourceName = fmt.Sprintf("test-resource-%s", testutils.GenerateRandomSuffix(6))
@@ -0,0 +1,133 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+
+	argoprojv1alpha1 "github.com/argoproj/argo-cd/v3/pkg/apis/application/v1alpha1"
+	"github.com/dana-team/application-rbac-validator/internal/decision"
+	"github.com/dana-team/application-rbac-validator/internal/metrics"
+	"github.com/dana-team/application-rbac-validator/internal/utils"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// SetupApplicationSetWebhookWithManager registers the webhook for ApplicationSet in the manager.
+func SetupApplicationSetWebhookWithManager(mgr ctrl.Manager, config ValidatorConfig) error {
+	return ctrl.NewWebhookManagedBy(mgr).For(&argoprojv1alpha1.ApplicationSet{}).
+		WithValidator(&ApplicationSetCustomValidator{Client: mgr.GetClient(), ValidatorConfig: config.withManagerRecorder(mgr)}).
+		Complete()
+}
+
+// +kubebuilder:webhook:path=/validate-argoproj-io-v1alpha1-applicationset,mutating=false,failurePolicy=fail,sideEffects=None,groups=argoproj.io,resources=applicationsets,verbs=create;update,versions=v1alpha1,name=vapplicationset-v1alpha1.kb.io,admissionReviewVersions=v1
+
+type ApplicationSetCustomValidator struct {
+	ValidatorConfig
+	Client client.Client
+}
+
+// ValidateCreate implements webhook.CustomValidator so a webhook will be registered for the type ApplicationSet.
+func (v *ApplicationSetCustomValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	logger := zap.New().WithName("webhook")
+	appSet, ok := obj.(*argoprojv1alpha1.ApplicationSet)
+	if !ok {
+		return nil, fmt.Errorf("expected a ApplicationSet object but got %T", obj)
+	}
+	logger.Info("Validation for ApplicationSet upon creation", "name", appSet.GetName())
+
+	ctx = newAuditContext(ctx, "ApplicationSet", appSet, appSet.Spec.Template.Spec.Destination)
+
+	violations, err := validateApplicationSet(ctx, v.Client, appSet, v.ValidatorConfig)
+	return v.admitPerCluster(ctx, v.Client, "ApplicationSet", appSet, violations, err)
+}
+
+// ValidateUpdate implements webhook.CustomValidator so a webhook will be registered for the type ApplicationSet.
+func (v *ApplicationSetCustomValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	logger := zap.New().WithName("webhook")
+	newAppSet, ok := newObj.(*argoprojv1alpha1.ApplicationSet)
+	if !ok {
+		return nil, fmt.Errorf("expected a ApplicationSet object for the newObj but got %T", newObj)
+	}
+	oldAppSet, ok := oldObj.(*argoprojv1alpha1.ApplicationSet)
+	if !ok {
+		return nil, fmt.Errorf("expected a ApplicationSet object for the oldObj but got %T", oldObj)
+	}
+
+	logger.Info("Validation for ApplicationSet upon update", "name", newAppSet.GetName())
+
+	if utils.IsNotApplicationSetSpecUpdate(oldAppSet, newAppSet) {
+		logger.V(-1).Info("Only a status update, approving automatically.")
+		v.approve(ctx, "ApplicationSet", newAppSet, metrics.ReasonNotSpecUpdate, newAppSet.Spec.Template.Spec.Destination)
+		return nil, nil
+	}
+
+	ctx = newAuditContext(ctx, "ApplicationSet", newAppSet, newAppSet.Spec.Template.Spec.Destination)
+
+	violations, err := validateApplicationSet(ctx, v.Client, newAppSet, v.ValidatorConfig)
+	return v.admitPerCluster(ctx, v.Client, "ApplicationSet", newAppSet, violations, err)
+}
+
+// ValidateDelete implements webhook.CustomValidator so a webhook will be registered for the type ApplicationSet.
+func (v *ApplicationSetCustomValidator) ValidateDelete(_ context.Context, _ runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// validateApplicationSet runs every Application the ApplicationSet generates, as far as it can be resolved at
+// admission time, through the same checks as a standalone Application and reports all violations at once, keyed by
+// the destination cluster of the generated Application they were found on.
+func validateApplicationSet(ctx context.Context, k8sClient client.Client, appSet *argoprojv1alpha1.ApplicationSet, config ValidatorConfig) (*clusterViolations, error) {
+	logger := zap.New().WithName("webhook").WithValues("applicationSet", appSet.GetName())
+	violations := newClusterViolations(fmt.Sprintf("ApplicationSet %s would generate unauthorized Applications", appSet.Name))
+
+	applications, err := utils.GenerateStaticApplications(ctx, k8sClient, appSet)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve the ApplicationSet's generated Applications: %w", err)
+	}
+
+	if len(applications) == 0 {
+		logger.Info("No statically resolvable Applications, deferring validation to the generated Applications")
+		return violations, nil
+	}
+
+	validated := map[string]bool{}
+	for _, generated := range applications {
+		application := generated.Application
+		destination := application.Spec.Destination
+		key := fmt.Sprintf("%s|%s|%s|%s", application.Name, destination.Server, destination.Name, destination.Namespace)
+		if validated[key] {
+			continue
+		}
+		validated[key] = true
+
+		logger.Info("Validating generated Application", "name", application.Name)
+		resolved := &resolution{}
+		if err := validateApplication(ctx, k8sClient, application, appSet, config, resolved); err != nil {
+			err = decision.WithFieldPrefix(err, generated.TemplateField)
+			violations.add(resolved, fmt.Errorf("application %q (destination server %q, name %q, namespace %q): %w",
+				application.Name, destination.Server, destination.Name, destination.Namespace, err))
+			continue
+		}
+		violations.add(resolved, nil)
+	}
+
+	return violations, nil
+}
@@ -0,0 +1,171 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	argoprojv1alpha1 "github.com/argoproj/argo-cd/v3/pkg/apis/application/v1alpha1"
+	"github.com/dana-team/application-rbac-validator/internal/clusterclient"
+	"github.com/dana-team/application-rbac-validator/internal/common"
+	"github.com/dana-team/application-rbac-validator/internal/utils"
+	testutils "github.com/dana-team/application-rbac-validator/test/utils"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("application-rbac-validator ApplicationSet Webhook", func() {
+	Context("On ApplicationSet validation", func() {
+		ctx := context.Background()
+
+		common.WebhookNamespacePath = testutils.WebhookNamespaceTestPath
+
+		var (
+			testNamespace string
+			testValidator ApplicationSetCustomValidator
+		)
+
+		BeforeEach(func() {
+			testValidator = ApplicationSetCustomValidator{Client: k8sClient,
+				ValidatorConfig: ValidatorConfig{ServerUrlDomain: testutils.TestDomain,
+					ClusterClients: clusterclient.NewStaticProvider(testutils.NewMockedDestinationClusterClient())}}
+
+			testNamespace = fmt.Sprintf("test-ns-%s", testutils.GenerateRandomSuffix(6))
+
+			By("creating the test namespace")
+			Expect(k8sClient.Create(ctx, &corev1.Namespace{
+				ObjectMeta: metav1.ObjectMeta{Name: testNamespace},
+			})).To(Succeed())
+
+			By("creating a file that stores the webhook's current namespace name")
+			Expect(os.MkdirAll(testutils.WebhookNamespaceDir, 0755)).To(Succeed())
+			Expect(os.WriteFile(testutils.WebhookNamespaceTestPath, []byte(testNamespace), 0644)).To(Succeed())
+
+			By("creating the argo instance ConfigMap for testing")
+			Expect(k8sClient.Create(ctx, &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      common.ArgoInstanceConfigMapName,
+					Namespace: testNamespace,
+				},
+				Data: map[string]string{
+					common.ArgoInstanceUsersConfigMapKey: testutils.ArgoInstanceUsersConfigMapData,
+					common.ArgoInstanceNameConfigMapKey:  testutils.ArgoInstanceNameConfigMapData,
+				},
+			})).To(Succeed())
+
+			By("creating the ConfigMap that stores the destination server token")
+			Expect(k8sClient.Create(ctx, &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      common.ClusterTokensConfigMapName,
+					Namespace: testNamespace,
+				},
+				Data: map[string]string{
+					utils.FormatFileSafeServerURL(testutils.TestDestinationServerUrl) + "-token": "dummy-token-content",
+				},
+			})).To(Succeed())
+		})
+
+		AfterEach(func() {
+			By("cleaning up the test namespace")
+			Expect(k8sClient.Delete(ctx, &corev1.Namespace{
+				ObjectMeta: metav1.ObjectMeta{Name: testNamespace},
+			})).To(Succeed())
+		})
+
+		newApplicationSet := func(template argoprojv1alpha1.ApplicationSetTemplate, elements ...string) *argoprojv1alpha1.ApplicationSet {
+			appSet := &argoprojv1alpha1.ApplicationSet{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      fmt.Sprintf("test-appset-%s", testutils.GenerateRandomSuffix(6)),
+					Namespace: testNamespace,
+				},
+				Spec: argoprojv1alpha1.ApplicationSetSpec{Template: template},
+			}
+			if len(elements) > 0 {
+				list := &argoprojv1alpha1.ListGenerator{}
+				for _, element := range elements {
+					list.Elements = append(list.Elements, apiextensionsv1.JSON{Raw: []byte(element)})
+				}
+				appSet.Spec.Generators = []argoprojv1alpha1.ApplicationSetGenerator{{List: list}}
+			}
+			return appSet
+		}
+
+		listTemplate := argoprojv1alpha1.ApplicationSetTemplate{
+			ApplicationSetTemplateMeta: argoprojv1alpha1.ApplicationSetTemplateMeta{Name: "{{cluster}}-app"},
+			Spec: argoprojv1alpha1.ApplicationSpec{
+				Destination: argoprojv1alpha1.ApplicationDestination{
+					Server:    "{{cluster}}",
+					Namespace: testutils.TestDestinationNamespace,
+				},
+			},
+		}
+
+		It("should allow an ApplicationSet with a static authorized template", func() {
+			appSet := newApplicationSet(argoprojv1alpha1.ApplicationSetTemplate{
+				ApplicationSetTemplateMeta: argoprojv1alpha1.ApplicationSetTemplateMeta{Name: "static-app"},
+				Spec: argoprojv1alpha1.ApplicationSpec{
+					Destination: argoprojv1alpha1.ApplicationDestination{
+						Server:    testutils.TestDestinationServerUrl,
+						Namespace: testutils.TestDestinationNamespace,
+					},
+				},
+			})
+
+			_, err := testValidator.ValidateCreate(ctx, appSet)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("should allow an ApplicationSet whose list elements are all authorized", func() {
+			appSet := newApplicationSet(listTemplate,
+				fmt.Sprintf(`{"cluster":%q}`, testutils.TestDestinationServerName))
+
+			_, err := testValidator.ValidateCreate(ctx, appSet)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("should reject an ApplicationSet with a list element targeting the current cluster", func() {
+			appSet := newApplicationSet(listTemplate,
+				fmt.Sprintf(`{"cluster":%q}`, testutils.TestDestinationServerName),
+				fmt.Sprintf(`{"cluster":%q}`, common.InClusterValues[0]))
+
+			_, err := testValidator.ValidateCreate(ctx, appSet)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring(common.InClusterValues[0] + "-app"))
+		})
+
+		It("should defer validation when the destination cannot be resolved statically", func() {
+			appSet := newApplicationSet(argoprojv1alpha1.ApplicationSetTemplate{
+				ApplicationSetTemplateMeta: argoprojv1alpha1.ApplicationSetTemplateMeta{Name: "{{path.basename}}"},
+				Spec: argoprojv1alpha1.ApplicationSpec{
+					Destination: argoprojv1alpha1.ApplicationDestination{
+						Server:    common.InClusterValues[0],
+						Namespace: "{{path.basename}}",
+					},
+				},
+			})
+
+			_, err := testValidator.ValidateCreate(ctx, appSet)
+			Expect(err).ToNot(HaveOccurred())
+		})
+	})
+})
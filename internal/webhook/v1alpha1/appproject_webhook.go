@@ -0,0 +1,223 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"time"
+
+	argoprojv1alpha1 "github.com/argoproj/argo-cd/v3/pkg/apis/application/v1alpha1"
+	rbacv1alpha1 "github.com/dana-team/application-rbac-validator/api/v1alpha1"
+	"github.com/dana-team/application-rbac-validator/internal/audit"
+	"github.com/dana-team/application-rbac-validator/internal/decision"
+	"github.com/dana-team/application-rbac-validator/internal/metrics"
+	"github.com/dana-team/application-rbac-validator/internal/utils"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// SetupAppProjectWebhookWithManager registers the webhook for AppProject in the manager.
+func SetupAppProjectWebhookWithManager(mgr ctrl.Manager, config ValidatorConfig) error {
+	return ctrl.NewWebhookManagedBy(mgr).For(&argoprojv1alpha1.AppProject{}).
+		WithValidator(&AppProjectCustomValidator{Client: mgr.GetClient(), ValidatorConfig: config.withManagerRecorder(mgr)}).
+		Complete()
+}
+
+// +kubebuilder:webhook:path=/validate-argoproj-io-v1alpha1-appproject,mutating=false,failurePolicy=fail,sideEffects=None,groups=argoproj.io,resources=appprojects,verbs=create;update,versions=v1alpha1,name=vappproject-v1alpha1.kb.io,admissionReviewVersions=v1
+
+type AppProjectCustomValidator struct {
+	ValidatorConfig
+	Client client.Client
+}
+
+// ValidateCreate implements webhook.CustomValidator so a webhook will be registered for the type AppProject.
+func (v *AppProjectCustomValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	logger := zap.New().WithName("webhook")
+	project, ok := obj.(*argoprojv1alpha1.AppProject)
+	if !ok {
+		return nil, fmt.Errorf("expected a AppProject object but got %T", obj)
+	}
+	logger.Info("Validation for AppProject upon creation", "name", project.GetName())
+
+	ctx = newAuditContext(ctx, "AppProject", project, project.Spec.Destinations...)
+
+	violations, err := validateAppProject(ctx, v.Client, project, v.ValidatorConfig)
+	return v.admitPerCluster(ctx, v.Client, "AppProject", project, violations, err)
+}
+
+// ValidateUpdate implements webhook.CustomValidator so a webhook will be registered for the type AppProject.
+func (v *AppProjectCustomValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	logger := zap.New().WithName("webhook")
+	newProject, ok := newObj.(*argoprojv1alpha1.AppProject)
+	if !ok {
+		return nil, fmt.Errorf("expected a AppProject object for the newObj but got %T", newObj)
+	}
+	oldProject, ok := oldObj.(*argoprojv1alpha1.AppProject)
+	if !ok {
+		return nil, fmt.Errorf("expected a AppProject object for the oldObj but got %T", oldObj)
+	}
+
+	logger.Info("Validation for AppProject upon update", "name", newProject.GetName())
+
+	if reflect.DeepEqual(oldProject.Spec.Destinations, newProject.Spec.Destinations) {
+		logger.V(-1).Info("Destinations did not change, approving automatically.")
+		v.approve(ctx, "AppProject", newProject, metrics.ReasonDestinationsUnchanged, newProject.Spec.Destinations...)
+		return nil, nil
+	}
+
+	ctx = newAuditContext(ctx, "AppProject", newProject, newProject.Spec.Destinations...)
+
+	violations, err := validateAppProject(ctx, v.Client, newProject, v.ValidatorConfig)
+	return v.admitPerCluster(ctx, v.Client, "AppProject", newProject, violations, err)
+}
+
+// ValidateDelete implements webhook.CustomValidator so a webhook will be registered for the type AppProject.
+func (v *AppProjectCustomValidator) ValidateDelete(_ context.Context, _ runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// validateAppProject ensures that every destination the AppProject grants is reachable by at least one of the
+// argo instance admins on the destination cluster, and reports all violations at once, keyed by the cluster of the
+// destination they were found on.
+func validateAppProject(ctx context.Context, k8sClient client.Client, project *argoprojv1alpha1.AppProject, config ValidatorConfig) (*clusterViolations, error) {
+	violations := newClusterViolations(fmt.Sprintf("AppProject %s grants unauthorized destinations", project.Name))
+
+	policy, err := utils.FetchApplicationRBACPolicy(ctx, k8sClient, project.Namespace)
+	if err != nil {
+		return nil, decision.Wrap(decision.ReasonInvalidConfiguration, "",
+			fmt.Errorf("failed to fetch AppProject's ApplicationRBACPolicy: %w", err))
+	}
+	if policy != nil {
+		violations.instance = policy.Spec.InstanceName
+	}
+
+	for i, destination := range project.Spec.Destinations {
+		resolved := &resolution{}
+		if err := validateAppProjectDestination(ctx, k8sClient, project, destination, policy, config, resolved); err != nil {
+			err = decision.WithFieldPrefix(err, fmt.Sprintf("spec.destinations[%d]", i))
+			violations.add(resolved, fmt.Errorf("destination (server %q, name %q, namespace %q): %w",
+				destination.Server, destination.Name, destination.Namespace, err))
+			continue
+		}
+		violations.add(resolved, nil)
+	}
+
+	return violations, nil
+}
+
+// validateAppProjectDestination validates a single AppProject destination. Negated entries only restrict the
+// project and are skipped, in-cluster entries the management Applications are exempt for are approved, while
+// wildcard entries are rejected unless a bypass label applies. The fields of its
+// denials are relative to the destination, and its destination cluster is recorded in the given resolution.
+func validateAppProjectDestination(ctx context.Context, k8sClient client.Client, project *argoprojv1alpha1.AppProject, destination argoprojv1alpha1.ApplicationDestination,
+	policy *rbacv1alpha1.ApplicationRBACPolicy, config ValidatorConfig, resolved *resolution) error {
+	logger := zap.New().WithName("webhook")
+	projectNamespace := project.Namespace
+
+	if utils.IsDenyPattern(destination.Server) || utils.IsDenyPattern(destination.Name) || utils.IsDenyPattern(destination.Namespace) {
+		logger.Info("Skipping negated destination", "destination", destination)
+		return nil
+	}
+
+	if destination.Namespace == "" || (destination.Server == "" && destination.Name == "") {
+		return decision.New(decision.ReasonDestinationMissing, missingDestinationField(destination),
+			"destination namespace and (server or name) must be specified")
+	}
+
+	isServerGlob := utils.IsGlobPattern(destination.Server) || utils.IsGlobPattern(destination.Name)
+	destServer := destination.Server
+	if !isServerGlob {
+		var err error
+		resolveCtx, resolveStage := startStage(ctx, metrics.StageServerResolution)
+		destServer, err = utils.ResolveServer(resolveCtx, k8sClient, projectNamespace, destination)
+		resolveStage.end(err)
+		if err != nil {
+			return decision.Wrap(decision.ReasonDestinationUnresolved, destinationServerField(destination),
+				fmt.Errorf("failed to resolve destination server: %w", err))
+		}
+	}
+	resolved.cluster = utils.ExtractClusterName(destServer)
+
+	logger.Info("Checking if bypass label exists on the AppProject's namespace", "destinationServer", destServer)
+	bypassCtx, bypassStage := startStage(ctx, metrics.StageBypassLookup)
+	isBypassLabelExists, err := utils.BypassLabelExists(bypassCtx, k8sClient, projectNamespace, utils.ExtractClusterName(destServer))
+	if err != nil {
+		bypassStage.end(err)
+		return fmt.Errorf("failed to check bypass label on the AppProject's namespace: %w", err)
+	}
+	if isBypassLabelExists {
+		bypassStage.end(nil)
+		logger.Info("Destination approved", "destinationServer", destServer)
+		audit.FromContext(ctx).SetBypass(audit.BypassLabel, "")
+		config.recorder().Eventf(project, corev1.EventTypeNormal, ReasonBypassedByLabel,
+			"Destination namespace %q in cluster %s approved by the bypass label of namespace %s",
+			destination.Namespace, destServer, projectNamespace)
+		return nil
+	}
+
+	logger.Info("Checking if a bypass grant covers the AppProject's destination", "destinationServer", destServer)
+	grant, err := utils.FindActiveBypassGrant(bypassCtx, k8sClient, projectNamespace, utils.ExtractClusterName(destServer),
+		destination.Namespace, time.Now())
+	bypassStage.end(err)
+	if err != nil {
+		return fmt.Errorf("failed to check bypass grants in the AppProject's namespace: %w", err)
+	}
+	if grant != nil {
+		logger.Info("Destination approved by bypass grant", "destinationServer", destServer, "bypassGrant", grant.Name,
+			"reason", grant.Spec.Reason, "approver", grant.Spec.Approver, "expiresAt", grant.Spec.ExpiresAt)
+		audit.FromContext(ctx).SetBypass(audit.BypassGrant, grant.Name)
+		config.recorder().Eventf(project, corev1.EventTypeNormal, ReasonBypassedByGrant,
+			"Destination namespace %q in cluster %s approved by BypassGrant %s (approver %s, expires at %s)",
+			destination.Namespace, destServer, grant.Name, grant.Spec.Approver, grant.Spec.ExpiresAt.UTC().Format(time.RFC3339))
+		return nil
+	}
+
+	if utils.IsInCluster(destServer) {
+		instanceName := ""
+		if policy != nil {
+			instanceName = policy.Spec.InstanceName
+		}
+		managementMatcher, err := utils.NewManagementMatcher(policy, instanceName)
+		if err != nil {
+			return decision.Wrap(decision.ReasonInvalidConfiguration, "",
+				fmt.Errorf("failed to build the management Application matcher: %w", err))
+		}
+		if managementMatcher.IsExemptDestination(destServer, destination.Namespace) {
+			logger.Info("Destination approved for the management Applications", "destinationServer", destServer)
+			audit.FromContext(ctx).SetBypass(audit.BypassManagement, "")
+			config.recorder().Eventf(project, corev1.EventTypeNormal, ReasonManagementApplication,
+				"Destination namespace %q in cluster %s approved for the management Applications", destination.Namespace, destServer)
+			return nil
+		}
+	}
+
+	if isServerGlob || utils.IsGlobPattern(destination.Namespace) {
+		field := "namespace"
+		if isServerGlob {
+			field = destinationServerField(destination)
+		}
+		return decision.New(decision.ReasonWildcardDestination, field, "wildcard destinations are not allowed without a bypass label")
+	}
+
+	return validateDestinationAccess(ctx, k8sClient, projectNamespace, destServer, destination.Namespace, policy, config)
+}
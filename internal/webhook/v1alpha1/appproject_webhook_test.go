@@ -0,0 +1,178 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	argoprojv1alpha1 "github.com/argoproj/argo-cd/v3/pkg/apis/application/v1alpha1"
+	rbacv1alpha1 "github.com/dana-team/application-rbac-validator/api/v1alpha1"
+	"github.com/dana-team/application-rbac-validator/internal/clusterclient"
+	"github.com/dana-team/application-rbac-validator/internal/common"
+	"github.com/dana-team/application-rbac-validator/internal/utils"
+	testutils "github.com/dana-team/application-rbac-validator/test/utils"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("application-rbac-validator AppProject Webhook", func() {
+	Context("On AppProject validation", func() {
+		ctx := context.Background()
+
+		common.WebhookNamespacePath = testutils.WebhookNamespaceTestPath
+
+		var (
+			testNamespace string
+			testValidator AppProjectCustomValidator
+		)
+
+		BeforeEach(func() {
+			testValidator = AppProjectCustomValidator{Client: k8sClient,
+				ValidatorConfig: ValidatorConfig{ServerUrlDomain: testutils.TestDomain,
+					ClusterClients: clusterclient.NewStaticProvider(testutils.NewMockedDestinationClusterClient())}}
+
+			testNamespace = fmt.Sprintf("test-ns-%s", testutils.GenerateRandomSuffix(6))
+
+			By("creating the test namespace")
+			Expect(k8sClient.Create(ctx, &corev1.Namespace{
+				ObjectMeta: metav1.ObjectMeta{Name: testNamespace},
+			})).To(Succeed())
+
+			By("creating a file that stores the webhook's current namespace name")
+			Expect(os.MkdirAll(testutils.WebhookNamespaceDir, 0755)).To(Succeed())
+			Expect(os.WriteFile(testutils.WebhookNamespaceTestPath, []byte(testNamespace), 0644)).To(Succeed())
+
+			By("creating the argo instance ConfigMap for testing")
+			Expect(k8sClient.Create(ctx, &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      common.ArgoInstanceConfigMapName,
+					Namespace: testNamespace,
+				},
+				Data: map[string]string{
+					common.ArgoInstanceUsersConfigMapKey: testutils.ArgoInstanceUsersConfigMapData,
+					common.ArgoInstanceNameConfigMapKey:  testutils.ArgoInstanceNameConfigMapData,
+				},
+			})).To(Succeed())
+
+			By("creating the ConfigMap that stores the destination server token")
+			Expect(k8sClient.Create(ctx, &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      common.ClusterTokensConfigMapName,
+					Namespace: testNamespace,
+				},
+				Data: map[string]string{
+					utils.FormatFileSafeServerURL(testutils.TestDestinationServerUrl) + "-token": "dummy-token-content",
+				},
+			})).To(Succeed())
+		})
+
+		AfterEach(func() {
+			By("cleaning up the test namespace")
+			Expect(k8sClient.Delete(ctx, &corev1.Namespace{
+				ObjectMeta: metav1.ObjectMeta{Name: testNamespace},
+			})).To(Succeed())
+		})
+
+		newAppProject := func(destinations ...argoprojv1alpha1.ApplicationDestination) *argoprojv1alpha1.AppProject {
+			return &argoprojv1alpha1.AppProject{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      fmt.Sprintf("test-project-%s", testutils.GenerateRandomSuffix(6)),
+					Namespace: testNamespace,
+				},
+				Spec: argoprojv1alpha1.AppProjectSpec{Destinations: destinations},
+			}
+		}
+
+		It("should allow an AppProject whose destinations the admins can access", func() {
+			project := newAppProject(argoprojv1alpha1.ApplicationDestination{
+				Server:    testutils.TestDestinationServerUrl,
+				Namespace: testutils.TestDestinationNamespace,
+			}, argoprojv1alpha1.ApplicationDestination{
+				Server:    "!" + testutils.TestDestinationServerUrl,
+				Namespace: "kube-system",
+			})
+
+			_, err := testValidator.ValidateCreate(ctx, project)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("should allow an AppProject targeting the current cluster for the management Application", func() {
+			project := newAppProject(argoprojv1alpha1.ApplicationDestination{
+				Server:    common.InClusterValues[0],
+				Namespace: testutils.TestDestinationNamespace,
+			})
+
+			_, err := testValidator.ValidateCreate(ctx, project)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("should reject an AppProject targeting the current cluster outside the management Applications' destinations", func() {
+			Expect(k8sClient.Create(ctx, &rbacv1alpha1.ApplicationRBACPolicy{
+				ObjectMeta: metav1.ObjectMeta{Name: "policy", Namespace: testNamespace},
+				Spec: rbacv1alpha1.ApplicationRBACPolicySpec{
+					InstanceName: testutils.ArgoInstanceNameConfigMapData,
+					Admins:       []rbacv1alpha1.PolicySubject{{Kind: "User", Name: "admin"}},
+					ManagementApplications: &rbacv1alpha1.ManagementApplications{
+						AllowedDestinations: []rbacv1alpha1.ManagementDestination{
+							{Cluster: common.InClusterValues[0], Namespace: "argocd-*"},
+						},
+					},
+				},
+			})).To(Succeed())
+
+			project := newAppProject(argoprojv1alpha1.ApplicationDestination{
+				Server:    common.InClusterValues[0],
+				Namespace: testutils.TestDestinationNamespace,
+			})
+
+			_, err := testValidator.ValidateCreate(ctx, project)
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should reject an AppProject with a wildcard destination", func() {
+			project := newAppProject(argoprojv1alpha1.ApplicationDestination{
+				Server:    "*",
+				Namespace: "*",
+			})
+
+			_, err := testValidator.ValidateCreate(ctx, project)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("wildcard"))
+		})
+
+		It("should allow an AppProject with a wildcard destination when the general bypass label exists", func() {
+			ns := &corev1.Namespace{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: testNamespace}, ns)).To(Succeed())
+			ns.Labels = map[string]string{common.AdminBypassLabel: common.LabelValueTrue}
+			Expect(k8sClient.Update(ctx, ns)).To(Succeed())
+
+			project := newAppProject(argoprojv1alpha1.ApplicationDestination{
+				Server:    "*",
+				Namespace: "*",
+			})
+
+			_, err := testValidator.ValidateCreate(ctx, project)
+			Expect(err).ToNot(HaveOccurred())
+		})
+	})
+})
@@ -0,0 +1,78 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"time"
+
+	argoprojv1alpha1 "github.com/argoproj/argo-cd/v3/pkg/apis/application/v1alpha1"
+	"github.com/dana-team/application-rbac-validator/internal/audit"
+	"github.com/dana-team/application-rbac-validator/internal/metrics"
+	"github.com/dana-team/application-rbac-validator/internal/tracing"
+	"go.opentelemetry.io/otel/trace"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// newAuditContext returns a copy of the given context carrying the audit record of the validation of the given
+// object of the given kind, filled from the admission request the context carries.
+func newAuditContext(ctx context.Context, kind string, obj client.Object, destinations ...argoprojv1alpha1.ApplicationDestination) context.Context {
+	record := &audit.Record{
+		Timestamp: time.Now().UTC(),
+		Kind:      kind,
+		Namespace: obj.GetNamespace(),
+		Name:      obj.GetName(),
+	}
+	if request, err := admission.RequestFromContext(ctx); err == nil {
+		record.UID = string(request.UID)
+		record.Operation = string(request.Operation)
+		record.User = request.UserInfo.Username
+		record.Groups = request.UserInfo.Groups
+	}
+	for _, destination := range destinations {
+		record.Destinations = append(record.Destinations, audit.Destination{
+			Server:    destination.Server,
+			Name:      destination.Name,
+			Namespace: destination.Namespace,
+		})
+	}
+
+	return audit.NewContext(ctx, record)
+}
+
+// stage is a validation stage being timed and traced.
+type stage struct {
+	ctx   context.Context
+	name  string
+	start time.Time
+	span  trace.Span
+}
+
+// startStage starts the given validation stage in a span, returning a copy of the given context carrying it.
+func startStage(ctx context.Context, name string) (context.Context, *stage) {
+	stageCtx, span := tracing.Start(ctx, name)
+	return stageCtx, &stage{ctx: ctx, name: name, start: time.Now(), span: span}
+}
+
+// end ends the validation stage with the given error, observing its duration in the metrics and in the audit
+// record the context carries.
+func (s *stage) end(err error) {
+	metrics.ObserveValidationStage(s.name, s.start)
+	audit.FromContext(s.ctx).ObserveStage(s.name, time.Since(s.start))
+	tracing.End(s.span, err)
+}
@@ -0,0 +1,175 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"time"
+
+	rbacv1alpha1 "github.com/dana-team/application-rbac-validator/api/v1alpha1"
+	admissionv1 "k8s.io/api/admission/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// DefaultBypassGrantMaxTTL is the longest a BypassGrant may stay active after it is created or its scope is changed.
+const DefaultBypassGrantMaxTTL = 7 * 24 * time.Hour
+
+// BypassGrantConfig configures the validation of BypassGrants.
+type BypassGrantConfig struct {
+	// ApproverGroups are the groups whose members may create a BypassGrant or change its scope. When empty, no one
+	// may.
+	ApproverGroups []string
+	// MaxTTL is the longest a BypassGrant may stay active after it is created or its scope is changed.
+	MaxTTL time.Duration
+}
+
+// SetupBypassGrantWebhookWithManager registers the webhooks for BypassGrant in the manager.
+func SetupBypassGrantWebhookWithManager(mgr ctrl.Manager, config BypassGrantConfig) error {
+	return ctrl.NewWebhookManagedBy(mgr).For(&rbacv1alpha1.BypassGrant{}).
+		WithDefaulter(&BypassGrantCustomDefaulter{}).
+		WithValidator(&BypassGrantCustomValidator{BypassGrantConfig: config}).
+		Complete()
+}
+
+// +kubebuilder:webhook:path=/mutate-argocd-dana-io-v1alpha1-bypassgrant,mutating=true,failurePolicy=fail,sideEffects=None,groups=argocd.dana.io,resources=bypassgrants,verbs=create;update,versions=v1alpha1,name=mbypassgrant-v1alpha1.kb.io,admissionReviewVersions=v1
+
+// BypassGrantCustomDefaulter records the user who created or changed a BypassGrant as its approver, so the approver
+// of a grant cannot be claimed by anyone else.
+type BypassGrantCustomDefaulter struct{}
+
+// Default implements webhook.CustomDefaulter so a webhook will be registered for the type BypassGrant.
+func (d *BypassGrantCustomDefaulter) Default(ctx context.Context, obj runtime.Object) error {
+	logger := zap.New().WithName("webhook")
+	grant, ok := obj.(*rbacv1alpha1.BypassGrant)
+	if !ok {
+		return fmt.Errorf("expected a BypassGrant object but got %T", obj)
+	}
+
+	req, err := admission.RequestFromContext(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get the admission request of BypassGrant %s: %w", grant.Name, err)
+	}
+
+	if req.Operation == admissionv1.Update {
+		oldGrant := &rbacv1alpha1.BypassGrant{}
+		if err := json.Unmarshal(req.OldObject.Raw, oldGrant); err != nil {
+			return fmt.Errorf("failed to decode the previous BypassGrant %s: %w", grant.Name, err)
+		}
+		if sameGrantScope(oldGrant.Spec, grant.Spec) {
+			grant.Spec.Approver = oldGrant.Spec.Approver
+			return nil
+		}
+	}
+
+	logger.Info("Recording the approver of BypassGrant", "name", grant.Name, "namespace", grant.Namespace,
+		"approver", req.UserInfo.Username)
+	grant.Spec.Approver = req.UserInfo.Username
+
+	return nil
+}
+
+// sameGrantScope checks whether the given grant specs bypass the same validations, ignoring their approvers.
+func sameGrantScope(spec, otherSpec rbacv1alpha1.BypassGrantSpec) bool {
+	spec.Approver, otherSpec.Approver = "", ""
+	return reflect.DeepEqual(spec, otherSpec)
+}
+
+// +kubebuilder:webhook:path=/validate-argocd-dana-io-v1alpha1-bypassgrant,mutating=false,failurePolicy=fail,sideEffects=None,groups=argocd.dana.io,resources=bypassgrants,verbs=create;update,versions=v1alpha1,name=vbypassgrant-v1alpha1.kb.io,admissionReviewVersions=v1
+
+// BypassGrantCustomValidator ensures that a BypassGrant is only created or re-scoped by a member of the approver
+// groups, so that the users bypassing validation cannot approve their own grants, and that it expires in the future
+// within the maximum TTL.
+type BypassGrantCustomValidator struct {
+	BypassGrantConfig
+}
+
+// ValidateCreate implements webhook.CustomValidator so a webhook will be registered for the type BypassGrant.
+func (v *BypassGrantCustomValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	grant, ok := obj.(*rbacv1alpha1.BypassGrant)
+	if !ok {
+		return nil, fmt.Errorf("expected a BypassGrant object but got %T", obj)
+	}
+
+	return nil, v.validateBypassGrant(ctx, grant, time.Now())
+}
+
+// ValidateUpdate implements webhook.CustomValidator so a webhook will be registered for the type BypassGrant.
+func (v *BypassGrantCustomValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	newGrant, ok := newObj.(*rbacv1alpha1.BypassGrant)
+	if !ok {
+		return nil, fmt.Errorf("expected a BypassGrant object for the newObj but got %T", newObj)
+	}
+	oldGrant, ok := oldObj.(*rbacv1alpha1.BypassGrant)
+	if !ok {
+		return nil, fmt.Errorf("expected a BypassGrant object for the oldObj but got %T", oldObj)
+	}
+
+	if sameGrantScope(oldGrant.Spec, newGrant.Spec) {
+		return nil, nil
+	}
+
+	return nil, v.validateBypassGrant(ctx, newGrant, time.Now())
+}
+
+// ValidateDelete implements webhook.CustomValidator so a webhook will be registered for the type BypassGrant.
+func (v *BypassGrantCustomValidator) ValidateDelete(_ context.Context, _ runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// validateBypassGrant ensures that the requesting user is a member of an approver group, and that the grant expires
+// after the given time and within the maximum TTL from it.
+func (v *BypassGrantCustomValidator) validateBypassGrant(ctx context.Context, grant *rbacv1alpha1.BypassGrant, now time.Time) error {
+	req, err := admission.RequestFromContext(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get the admission request of BypassGrant %s: %w", grant.Name, err)
+	}
+
+	isApprover := slices.ContainsFunc(req.UserInfo.Groups, func(group string) bool {
+		return slices.Contains(v.ApproverGroups, group)
+	})
+	if !isApprover {
+		return errors.NewForbidden(rbacv1alpha1.GroupVersion.WithResource("bypassgrants").GroupResource(), grant.Name,
+			fmt.Errorf("user %s is not a member of the BypassGrant approver groups %v", req.UserInfo.Username, v.ApproverGroups))
+	}
+
+	expiresAtPath := field.NewPath("spec", "expiresAt")
+	expiresAt := grant.Spec.ExpiresAt.Time
+	var fieldErrs field.ErrorList
+	switch {
+	case expiresAt.IsZero():
+		fieldErrs = append(fieldErrs, field.Required(expiresAtPath, "the grant must expire"))
+	case !now.Before(expiresAt):
+		fieldErrs = append(fieldErrs, field.Invalid(expiresAtPath, expiresAt.UTC().Format(time.RFC3339), "must be in the future"))
+	case expiresAt.Sub(now) > v.MaxTTL:
+		fieldErrs = append(fieldErrs, field.Invalid(expiresAtPath, expiresAt.UTC().Format(time.RFC3339),
+			fmt.Sprintf("must be at most %s from now", v.MaxTTL)))
+	}
+	if len(fieldErrs) > 0 {
+		return errors.NewInvalid(rbacv1alpha1.GroupVersion.WithKind("BypassGrant").GroupKind(), grant.Name, fieldErrs)
+	}
+
+	return nil
+}
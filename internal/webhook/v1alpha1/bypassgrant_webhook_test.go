@@ -0,0 +1,179 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	rbacv1alpha1 "github.com/dana-team/application-rbac-validator/api/v1alpha1"
+	admissionv1 "k8s.io/api/admission/v1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("application-rbac-validator BypassGrant Webhook", func() {
+	Context("On BypassGrant defaulting", func() {
+		var (
+			grant         *rbacv1alpha1.BypassGrant
+			testDefaulter BypassGrantCustomDefaulter
+		)
+
+		requestContext := func(operation admissionv1.Operation, username string, oldGrant *rbacv1alpha1.BypassGrant) context.Context {
+			return newBypassGrantRequestContext(operation, authenticationv1.UserInfo{Username: username}, oldGrant)
+		}
+
+		BeforeEach(func() {
+			testDefaulter = BypassGrantCustomDefaulter{}
+			grant = newTestBypassGrant()
+		})
+
+		It("should set the approver to the creating user", func() {
+			Expect(testDefaulter.Default(requestContext(admissionv1.Create, "team-a-admin", nil), grant)).To(Succeed())
+			Expect(grant.Spec.Approver).To(Equal("team-a-admin"))
+		})
+
+		It("should set the approver to the user changing the scope of the grant", func() {
+			oldGrant := grant.DeepCopy()
+			oldGrant.Spec.Approver = "platform-admin"
+			grant.Spec.ExpiresAt = metav1.NewTime(grant.Spec.ExpiresAt.Add(24 * time.Hour))
+
+			Expect(testDefaulter.Default(requestContext(admissionv1.Update, "team-a-admin", oldGrant), grant)).To(Succeed())
+			Expect(grant.Spec.Approver).To(Equal("team-a-admin"))
+		})
+
+		It("should keep the approver when only the approver is changed", func() {
+			oldGrant := grant.DeepCopy()
+			oldGrant.Spec.Approver = "platform-admin"
+
+			Expect(testDefaulter.Default(requestContext(admissionv1.Update, "team-a-admin", oldGrant), grant)).To(Succeed())
+			Expect(grant.Spec.Approver).To(Equal("platform-admin"))
+		})
+	})
+
+	Context("On BypassGrant validation", func() {
+		const approverGroup = "platform-approvers"
+
+		var (
+			grant         *rbacv1alpha1.BypassGrant
+			testValidator BypassGrantCustomValidator
+		)
+
+		approver := authenticationv1.UserInfo{Username: "platform-admin", Groups: []string{"system:authenticated", approverGroup}}
+		tenant := authenticationv1.UserInfo{Username: "team-a-admin", Groups: []string{"system:authenticated"}}
+
+		BeforeEach(func() {
+			testValidator = BypassGrantCustomValidator{BypassGrantConfig: BypassGrantConfig{
+				ApproverGroups: []string{approverGroup},
+				MaxTTL:         DefaultBypassGrantMaxTTL,
+			}}
+			grant = newTestBypassGrant()
+		})
+
+		It("should allow a grant created by a member of an approver group", func() {
+			_, err := testValidator.ValidateCreate(newBypassGrantRequestContext(admissionv1.Create, approver, nil), grant)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should reject a grant created by a user outside the approver groups", func() {
+			_, err := testValidator.ValidateCreate(newBypassGrantRequestContext(admissionv1.Create, tenant, nil), grant)
+			Expect(errors.IsForbidden(err)).To(BeTrue())
+		})
+
+		It("should reject every grant when no approver group is configured", func() {
+			testValidator.ApproverGroups = nil
+
+			_, err := testValidator.ValidateCreate(newBypassGrantRequestContext(admissionv1.Create, approver, nil), grant)
+			Expect(errors.IsForbidden(err)).To(BeTrue())
+		})
+
+		It("should reject a grant that already expired", func() {
+			grant.Spec.ExpiresAt = metav1.NewTime(time.Now().Add(-time.Minute))
+
+			_, err := testValidator.ValidateCreate(newBypassGrantRequestContext(admissionv1.Create, approver, nil), grant)
+			Expect(errors.IsInvalid(err)).To(BeTrue())
+		})
+
+		It("should reject a grant without an expiry", func() {
+			grant.Spec.ExpiresAt = metav1.Time{}
+
+			_, err := testValidator.ValidateCreate(newBypassGrantRequestContext(admissionv1.Create, approver, nil), grant)
+			Expect(errors.IsInvalid(err)).To(BeTrue())
+		})
+
+		It("should reject a grant expiring after the maximum TTL", func() {
+			grant.Spec.ExpiresAt = metav1.NewTime(time.Now().Add(DefaultBypassGrantMaxTTL + time.Hour))
+
+			_, err := testValidator.ValidateCreate(newBypassGrantRequestContext(admissionv1.Create, approver, nil), grant)
+			Expect(errors.IsInvalid(err)).To(BeTrue())
+		})
+
+		It("should reject a user outside the approver groups extending a grant", func() {
+			oldGrant := grant.DeepCopy()
+			grant.Spec.ExpiresAt = metav1.NewTime(grant.Spec.ExpiresAt.Add(24 * time.Hour))
+
+			_, err := testValidator.ValidateUpdate(newBypassGrantRequestContext(admissionv1.Update, tenant, oldGrant), oldGrant, grant)
+			Expect(errors.IsForbidden(err)).To(BeTrue())
+		})
+
+		It("should allow any user to update a grant without changing its scope", func() {
+			grant.Spec.ExpiresAt = metav1.NewTime(time.Now().Add(-time.Minute))
+			oldGrant := grant.DeepCopy()
+			grant.Labels = map[string]string{"team": "a"}
+
+			_, err := testValidator.ValidateUpdate(newBypassGrantRequestContext(admissionv1.Update, tenant, oldGrant), oldGrant, grant)
+			Expect(err).NotTo(HaveOccurred())
+		})
+	})
+})
+
+// newTestBypassGrant returns a BypassGrant expiring in an hour.
+func newTestBypassGrant() *rbacv1alpha1.BypassGrant {
+	return &rbacv1alpha1.BypassGrant{
+		ObjectMeta: metav1.ObjectMeta{Name: "grant", Namespace: "argocd-team-a"},
+		Spec: rbacv1alpha1.BypassGrantSpec{
+			Cluster:   "team-b-prod",
+			Reason:    "Migrating the shared ingress controller",
+			Approver:  "platform-oncall",
+			ExpiresAt: metav1.NewTime(time.Now().Add(time.Hour).Truncate(time.Second)),
+		},
+	}
+}
+
+// newBypassGrantRequestContext returns a context carrying the admission request of the given user for the given
+// operation on a BypassGrant, whose previous version is the given grant on updates.
+func newBypassGrantRequestContext(operation admissionv1.Operation, userInfo authenticationv1.UserInfo,
+	oldGrant *rbacv1alpha1.BypassGrant) context.Context {
+	req := admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+		Operation: operation,
+		UserInfo:  userInfo,
+	}}
+	if oldGrant != nil {
+		raw, err := json.Marshal(oldGrant)
+		Expect(err).NotTo(HaveOccurred())
+		req.OldObject = runtime.RawExtension{Raw: raw}
+	}
+
+	return admission.NewContextWithRequest(context.Background(), req)
+}
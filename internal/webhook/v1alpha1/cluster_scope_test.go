@@ -0,0 +1,137 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/dana-team/application-rbac-validator/internal/clusterclient"
+	"github.com/dana-team/application-rbac-validator/internal/common"
+	"github.com/dana-team/application-rbac-validator/internal/utils"
+	testutils "github.com/dana-team/application-rbac-validator/test/utils"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("application-rbac-validator cluster-scoped validation", func() {
+	Context("On Application validation with cluster-scoped validation enabled", func() {
+		ctx := context.Background()
+
+		common.WebhookNamespacePath = testutils.WebhookNamespaceTestPath
+
+		var testNamespace string
+
+		newValidator := func(clusterScopedValidation bool) ApplicationCustomValidator {
+			return ApplicationCustomValidator{Client: k8sClient,
+				ValidatorConfig: ValidatorConfig{ServerUrlDomain: testutils.TestDomain,
+					ClusterClients:          clusterclient.NewStaticProvider(testutils.NewMockedDestinationClusterClient()),
+					ClusterScopedValidation: clusterScopedValidation}}
+		}
+
+		createArgoConfig := func(users string) {
+			Expect(k8sClient.Create(ctx, &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      common.ArgoInstanceConfigMapName,
+					Namespace: testNamespace,
+				},
+				Data: map[string]string{
+					common.ArgoInstanceUsersConfigMapKey: users,
+					common.ArgoInstanceNameConfigMapKey:  testutils.ArgoInstanceNameConfigMapData,
+				},
+			})).To(Succeed())
+		}
+
+		BeforeEach(func() {
+			testNamespace = fmt.Sprintf("test-ns-%s", testutils.GenerateRandomSuffix(6))
+
+			By("creating the test namespace")
+			Expect(k8sClient.Create(ctx, &corev1.Namespace{
+				ObjectMeta: metav1.ObjectMeta{Name: testNamespace},
+			})).To(Succeed())
+
+			By("creating a file that stores the webhook's current namespace name")
+			Expect(os.MkdirAll(testutils.WebhookNamespaceDir, 0755)).To(Succeed())
+			Expect(os.WriteFile(testutils.WebhookNamespaceTestPath, []byte(testNamespace), 0644)).To(Succeed())
+
+			By("creating the ConfigMap that stores the destination server token")
+			Expect(k8sClient.Create(ctx, &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      common.ClusterTokensConfigMapName,
+					Namespace: testNamespace,
+				},
+				Data: map[string]string{
+					utils.FormatFileSafeServerURL(testutils.TestDestinationServerUrl) + "-token": "dummy-token-content",
+				},
+			})).To(Succeed())
+		})
+
+		AfterEach(func() {
+			By("cleaning up the test namespace")
+			Expect(k8sClient.Delete(ctx, &corev1.Namespace{
+				ObjectMeta: metav1.ObjectMeta{Name: testNamespace},
+			})).To(Succeed())
+		})
+
+		It("should reject an Application without a destination namespace when disabled", func() {
+			createArgoConfig(testutils.ArgoInstanceUsersConfigMapData)
+			validator := newValidator(false)
+
+			_, err := validator.ValidateCreate(ctx, testutils.GenerateTestApplication(testNamespace, testutils.TestDestinationServerUrl, ""))
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should allow an Application without a destination namespace when an admin has cluster-scoped access", func() {
+			createArgoConfig(testutils.ArgoInstanceUsersConfigMapData)
+			validator := newValidator(true)
+
+			_, err := validator.ValidateCreate(ctx, testutils.GenerateTestApplication(testNamespace, testutils.TestDestinationServerUrl, ""))
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("should reject an Application without a destination namespace when no admin has cluster-scoped access", func() {
+			createArgoConfig(testutils.InvalidArgoInstanceUsersConfigMapData)
+			validator := newValidator(true)
+
+			_, err := validator.ValidateCreate(ctx, testutils.GenerateTestApplication(testNamespace, testutils.TestDestinationServerUrl, ""))
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("cluster-scoped"))
+		})
+
+		It("should validate cluster-scoped access for a destination cluster allowing cluster resources", func() {
+			createArgoConfig(testutils.ArgoInstanceUsersConfigMapData)
+			Expect(k8sClient.Create(ctx, &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      testutils.ClusterHostname + "-" + common.SecretNameSuffix,
+					Namespace: testNamespace,
+				},
+				Data: map[string][]byte{
+					common.ClusterResourcesKey: []byte(common.LabelValueTrue),
+				},
+			})).To(Succeed())
+			validator := newValidator(true)
+
+			_, err := validator.ValidateCreate(ctx, testutils.GenerateTestApplication(testNamespace,
+				testutils.TestDestinationServerUrl, testutils.TestDestinationNamespace))
+			Expect(err).ToNot(HaveOccurred())
+		})
+	})
+})
@@ -0,0 +1,86 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"github.com/dana-team/application-rbac-validator/internal/audit"
+	"github.com/dana-team/application-rbac-validator/internal/clusterclient"
+	"github.com/dana-team/application-rbac-validator/internal/common"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+// defaultClusterClients is the pool used by validators that are not given a cluster client provider.
+var defaultClusterClients = clusterclient.NewPool(clusterclient.DefaultIdleTimeout)
+
+// ValidatorConfig holds the settings shared by the validating webhooks.
+type ValidatorConfig struct {
+	// ServerUrlDomain is the domain used to build a destination server URL from a cluster name.
+	ServerUrlDomain string
+	// EnforcementMode is the global enforcement mode, either "enforce" or "warn".
+	EnforcementMode string
+	// ClusterEnforcementModes overrides the global enforcement mode per destination cluster name.
+	ClusterEnforcementModes map[string]string
+	// ClusterScopedValidation enables validating cluster-scoped access for destinations without a namespace and for
+	// destination clusters whose secret allows cluster resources.
+	ClusterScopedValidation bool
+	// ClusterSecretsNamespace is the namespace of the Argo CD cluster secrets whose credentials are used to access
+	// the destination clusters. The cluster tokens ConfigMap is used when it is empty or holds no matching secret.
+	ClusterSecretsNamespace string
+	// ClusterClients provides the clients of the destination clusters. A shared pool is used when it is not set.
+	ClusterClients clusterclient.Provider
+	// Recorder records Events about denials and bypassed validations. The webhooks set up with a manager record them
+	// with the recorder of the manager when it is not set, and Events are discarded otherwise.
+	Recorder record.EventRecorder
+	// Auditor emits the audit records of the validation decisions. Auditing is disabled when it is not set.
+	Auditor *audit.Auditor
+}
+
+// clusterClients returns the provider of the destination cluster clients.
+func (c *ValidatorConfig) clusterClients() clusterclient.Provider {
+	if c.ClusterClients == nil {
+		return defaultClusterClients
+	}
+	return c.ClusterClients
+}
+
+// recorder returns the recorder of the Events about admission decisions.
+func (c *ValidatorConfig) recorder() record.EventRecorder {
+	if c.Recorder == nil {
+		return noopRecorder{}
+	}
+	return c.Recorder
+}
+
+// withManagerRecorder returns the config with the Event recorder of the given manager if it has no recorder.
+func (c ValidatorConfig) withManagerRecorder(mgr ctrl.Manager) ValidatorConfig {
+	if c.Recorder == nil {
+		c.Recorder = mgr.GetEventRecorderFor(common.EventRecorderName)
+	}
+	return c
+}
+
+// noopRecorder is an Event recorder that discards every Event.
+type noopRecorder struct{}
+
+func (noopRecorder) Event(runtime.Object, string, string, string) {}
+
+func (noopRecorder) Eventf(runtime.Object, string, string, string, ...any) {}
+
+func (noopRecorder) AnnotatedEventf(runtime.Object, map[string]string, string, string, string, ...any) {
+}
@@ -0,0 +1,227 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+
+	argoprojv1alpha1 "github.com/argoproj/argo-cd/v3/pkg/apis/application/v1alpha1"
+	"github.com/dana-team/application-rbac-validator/internal/audit"
+	"github.com/dana-team/application-rbac-validator/internal/common"
+	"github.com/dana-team/application-rbac-validator/internal/decision"
+	"github.com/dana-team/application-rbac-validator/internal/metrics"
+	"github.com/dana-team/application-rbac-validator/internal/utils"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// resolution holds what the validation of a destination resolved, so that admitting it does not resolve it again.
+type resolution struct {
+	// cluster is the name of the destination cluster, empty if the destination server could not be resolved.
+	cluster string
+	// instance is the name of the Argo instance, empty if the validation ended before it was needed.
+	instance string
+}
+
+// clusterViolations holds the violations found on each destination cluster of an object spanning several
+// destinations, keyed by cluster name, along with the Argo instance the destinations were validated for. Clusters
+// validated without violations have no errors.
+type clusterViolations struct {
+	summary   string
+	instance  string
+	byCluster map[string][]error
+}
+
+// newClusterViolations returns clusterViolations whose violations are reported under the given summary.
+func newClusterViolations(summary string) *clusterViolations {
+	return &clusterViolations{summary: summary, byCluster: map[string][]error{}}
+}
+
+// add records the result of the validation of a destination resolved to the given resolution.
+func (v *clusterViolations) add(resolved *resolution, err error) {
+	if resolved.instance != "" {
+		v.instance = resolved.instance
+	}
+	if err == nil {
+		if _, ok := v.byCluster[resolved.cluster]; !ok {
+			v.byCluster[resolved.cluster] = nil
+		}
+		return
+	}
+	v.byCluster[resolved.cluster] = append(v.byCluster[resolved.cluster], err)
+}
+
+// join returns the given violations reported under the summary.
+func (v *clusterViolations) join(errs []error) error {
+	return fmt.Errorf("%s: %w", v.summary, errors.Join(errs...))
+}
+
+// admit applies the enforcement mode of the object's namespace and destination cluster to the result of its
+// validation. In warn mode a violation is logged, counted and returned as an admission warning, and the object
+// is admitted. If the enforcement mode cannot be determined, the violation is enforced. Enforced violations are
+// denied with a Forbidden status carrying the reason, field and remediation of every denial as a cause. Every
+// decision is counted by outcome and reason code and emitted as an audit record, and violations are recorded as
+// Warning Events on the object. The destination cluster and Argo instance are the ones the validation resolved.
+func (c *ValidatorConfig) admit(ctx context.Context, k8sClient client.Client, kind string, obj client.Object, resolved *resolution, validationErr error) (admission.Warnings, error) {
+	clusters := []string{resolved.cluster}
+	if validationErr == nil {
+		c.decide(ctx, kind, metrics.OutcomeAllowed, "", clusters, resolved.instance, nil)
+		return nil, nil
+	}
+
+	if c.enforcementMode(ctx, k8sClient, kind, obj, resolved.cluster) != common.EnforcementModeWarn {
+		return nil, c.deny(ctx, kind, obj, clusters, resolved.instance, validationErr)
+	}
+
+	return c.warn(ctx, kind, obj, clusters, resolved.instance, validationErr), nil
+}
+
+// admitPerCluster applies the enforcement mode of each destination cluster of an object spanning several
+// destinations to the violations found on that cluster. The object is denied with the violations of the clusters
+// in enforce mode, admitted with a warning carrying the violations of the clusters in warn mode if there are none,
+// and admitted otherwise. A validation error not tied to a destination is admitted like a single destination's.
+func (c *ValidatorConfig) admitPerCluster(ctx context.Context, k8sClient client.Client, kind string, obj client.Object,
+	violations *clusterViolations, validationErr error) (admission.Warnings, error) {
+	if validationErr != nil {
+		return c.admit(ctx, k8sClient, kind, obj, &resolution{}, validationErr)
+	}
+
+	var enforced, warned []error
+	var clusters, enforcedClusters, warnedClusters []string
+	for _, clusterName := range slices.Sorted(maps.Keys(violations.byCluster)) {
+		clusters = append(clusters, clusterName)
+		errs := violations.byCluster[clusterName]
+		if len(errs) == 0 {
+			continue
+		}
+		if c.enforcementMode(ctx, k8sClient, kind, obj, clusterName) == common.EnforcementModeWarn {
+			warned = append(warned, errs...)
+			warnedClusters = append(warnedClusters, clusterName)
+		} else {
+			enforced = append(enforced, errs...)
+			enforcedClusters = append(enforcedClusters, clusterName)
+		}
+	}
+
+	switch {
+	case len(enforced) > 0:
+		return nil, c.deny(ctx, kind, obj, enforcedClusters, violations.instance, violations.join(enforced))
+	case len(warned) > 0:
+		return c.warn(ctx, kind, obj, warnedClusters, violations.instance, violations.join(warned)), nil
+	}
+
+	c.decide(ctx, kind, metrics.OutcomeAllowed, "", clusters, violations.instance, nil)
+	return nil, nil
+}
+
+// resolvedClusters returns the distinct given destination clusters, skipping the destinations whose cluster could
+// not be resolved.
+func resolvedClusters(clusters []string) []string {
+	resolved := slices.DeleteFunc(slices.Clone(clusters), func(cluster string) bool { return cluster == "" })
+	slices.Sort(resolved)
+	return slices.Compact(resolved)
+}
+
+// metricClusters returns the cluster label values a decision on the given resolved clusters is counted under, so
+// that it is counted once per destination cluster, or once without a cluster if none was resolved.
+func metricClusters(clusters []string) []string {
+	if len(clusters) == 0 {
+		return []string{""}
+	}
+
+	return clusters
+}
+
+// enforcementMode returns the enforcement mode of the object's namespace and the given destination cluster. If it
+// cannot be determined, the violation is enforced.
+func (c *ValidatorConfig) enforcementMode(ctx context.Context, k8sClient client.Client, kind string, obj client.Object, clusterName string) string {
+	mode, err := utils.ResolveEnforcementMode(ctx, k8sClient, obj.GetNamespace(), clusterName, c.EnforcementMode, c.ClusterEnforcementModes)
+	if err != nil {
+		zap.New().WithName("webhook").WithValues("kind", kind, "namespace", obj.GetNamespace(), "name", obj.GetName(), "cluster", clusterName).
+			Error(err, "Failed to resolve enforcement mode, enforcing")
+		return common.EnforcementModeEnforce
+	}
+
+	return mode
+}
+
+// warn counts and records the admission of the given Argo CD object of the given kind despite its violation on the
+// given destination clusters because the enforcement mode is warn, and returns the admission warning it is admitted
+// with.
+func (c *ValidatorConfig) warn(ctx context.Context, kind string, obj client.Object, clusters []string, instanceName string, validationErr error) admission.Warnings {
+	namespace, name := obj.GetNamespace(), obj.GetName()
+	reason := string(decision.ReasonOf(validationErr))
+	clusters = resolvedClusters(clusters)
+
+	zap.New().WithName("webhook").WithValues("kind", kind, "namespace", namespace, "name", name, "clusters", clusters).
+		Info("Admitting despite violation because the enforcement mode is warn", "violation", validationErr.Error())
+	for _, cluster := range metricClusters(clusters) {
+		metrics.IncValidationWarnings(kind, cluster)
+	}
+	c.decide(ctx, kind, metrics.OutcomeWarned, reason, clusters, instanceName, validationErr)
+	c.recorder().Eventf(obj, corev1.EventTypeWarning, ReasonAdmittedWithViolation,
+		"%s %s admitted because the enforcement mode is warn, but would be denied (%s): %s", kind, name, reason, validationErr.Error())
+
+	return admission.Warnings{fmt.Sprintf("%s %s would be denied once enforced: %s", kind, name, validationErr.Error())}
+}
+
+// deny counts and records the denial of the given Argo CD object of the given kind for its violation on the given
+// destination clusters, and converts its validation error into the error it is denied with. The reason of the
+// recorded Warning Event is the reason code of the denial.
+func (c *ValidatorConfig) deny(ctx context.Context, kind string, obj client.Object, clusters []string, instanceName string, validationErr error) error {
+	reason := string(decision.ReasonOf(validationErr))
+	c.decide(ctx, kind, metrics.OutcomeDenied, reason, clusters, instanceName, validationErr)
+	c.recorder().Eventf(obj, corev1.EventTypeWarning, reason, "%s %s denied: %s", kind, obj.GetName(), validationErr.Error())
+
+	return decision.ToStatusError(argoprojv1alpha1.SchemeGroupVersion.WithKind(kind).GroupKind(), obj.GetName(), validationErr)
+}
+
+// approve counts and records the admission of the given Argo CD object of the given kind without validation, for
+// the given reason.
+func (c *ValidatorConfig) approve(ctx context.Context, kind string, obj client.Object, reason string,
+	destinations ...argoprojv1alpha1.ApplicationDestination) {
+	ctx = newAuditContext(ctx, kind, obj, destinations...)
+	c.decide(ctx, kind, metrics.OutcomeAllowed, reason, nil, "", nil)
+}
+
+// decide counts the admission decision on an object of the given kind once per given destination cluster and emits
+// the audit record the context carries.
+func (c *ValidatorConfig) decide(ctx context.Context, kind, outcome, reason string, clusters []string, instanceName string, validationErr error) {
+	clusters = resolvedClusters(clusters)
+	for _, cluster := range metricClusters(clusters) {
+		metrics.IncAdmissionDecisions(kind, outcome, reason, cluster, instanceName)
+	}
+
+	record := audit.FromContext(ctx)
+	if record == nil {
+		return
+	}
+	record.Decision = outcome
+	record.Reason = reason
+	record.Clusters = clusters
+	record.Instance = instanceName
+	if validationErr != nil {
+		record.Message = validationErr.Error()
+	}
+	c.Auditor.Emit(record)
+}
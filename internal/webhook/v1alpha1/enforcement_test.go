@@ -0,0 +1,176 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+
+	argoprojv1alpha1 "github.com/argoproj/argo-cd/v3/pkg/apis/application/v1alpha1"
+	"github.com/dana-team/application-rbac-validator/internal/audit"
+	"github.com/dana-team/application-rbac-validator/internal/common"
+	"github.com/dana-team/application-rbac-validator/internal/metrics"
+	testutils "github.com/dana-team/application-rbac-validator/test/utils"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("application-rbac-validator enforcement modes", func() {
+	Context("On Application validation with a violation", func() {
+		ctx := context.Background()
+
+		var (
+			testNamespace string
+			application   *argoprojv1alpha1.Application
+		)
+
+		createNamespace := func(labels map[string]string) {
+			testNamespace = fmt.Sprintf("test-ns-%s", testutils.GenerateRandomSuffix(6))
+			Expect(k8sClient.Create(ctx, &corev1.Namespace{
+				ObjectMeta: metav1.ObjectMeta{Name: testNamespace, Labels: labels},
+			})).To(Succeed())
+
+			By("creating an Application targeting the current cluster")
+			application = &argoprojv1alpha1.Application{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      fmt.Sprintf("test-resource-%s", testutils.GenerateRandomSuffix(6)),
+					Namespace: testNamespace,
+				},
+				Spec: argoprojv1alpha1.ApplicationSpec{
+					Destination: argoprojv1alpha1.ApplicationDestination{
+						Namespace: testutils.TestDestinationNamespace,
+						Server:    common.InClusterValues[0],
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      common.ArgoInstanceConfigMapName,
+					Namespace: testNamespace,
+				},
+				Data: map[string]string{
+					common.ArgoInstanceNameConfigMapKey: testutils.ArgoInstanceNameConfigMapData,
+				},
+			})).To(Succeed())
+		}
+
+		AfterEach(func() {
+			By("cleaning up the test namespace")
+			Expect(k8sClient.Delete(ctx, &corev1.Namespace{
+				ObjectMeta: metav1.ObjectMeta{Name: testNamespace},
+			})).To(Succeed())
+		})
+
+		It("should deny by default", func() {
+			createNamespace(nil)
+			validator := ApplicationCustomValidator{Client: k8sClient}
+
+			warnings, err := validator.ValidateCreate(ctx, application)
+			Expect(err).To(HaveOccurred())
+			Expect(warnings).To(BeEmpty())
+		})
+
+		It("should admit with a warning when the global mode is warn", func() {
+			createNamespace(nil)
+			validator := ApplicationCustomValidator{Client: k8sClient,
+				ValidatorConfig: ValidatorConfig{EnforcementMode: common.EnforcementModeWarn}}
+
+			warnings, err := validator.ValidateCreate(ctx, application)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(warnings).To(HaveLen(1))
+		})
+
+		It("should admit with a warning when the destination cluster mode is warn", func() {
+			createNamespace(nil)
+			validator := ApplicationCustomValidator{Client: k8sClient,
+				ValidatorConfig: ValidatorConfig{ClusterEnforcementModes: map[string]string{
+					common.InClusterValues[0]: common.EnforcementModeWarn,
+				}}}
+
+			warnings, err := validator.ValidateCreate(ctx, application)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(warnings).To(HaveLen(1))
+		})
+
+		It("should deny when the namespace label overrides a global warn mode", func() {
+			createNamespace(map[string]string{common.EnforcementModeLabel: common.EnforcementModeEnforce})
+			validator := ApplicationCustomValidator{Client: k8sClient,
+				ValidatorConfig: ValidatorConfig{EnforcementMode: common.EnforcementModeWarn}}
+
+			_, err := validator.ValidateCreate(ctx, application)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Context("On updates admitted without validation", func() {
+		ctx := context.Background()
+
+		var (
+			buffer         bytes.Buffer
+			oldApplication *argoprojv1alpha1.Application
+			validator      ApplicationCustomValidator
+		)
+
+		BeforeEach(func() {
+			buffer.Reset()
+			validator = ApplicationCustomValidator{Client: k8sClient,
+				ValidatorConfig: ValidatorConfig{Auditor: audit.NewAuditor(audit.NewWriterSink(&buffer))}}
+			oldApplication = &argoprojv1alpha1.Application{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-resource", Namespace: "argocd-test"},
+				Spec: argoprojv1alpha1.ApplicationSpec{
+					Destination: argoprojv1alpha1.ApplicationDestination{
+						Namespace: testutils.TestDestinationNamespace,
+						Server:    common.InClusterValues[0],
+					},
+					SyncPolicy: &argoprojv1alpha1.SyncPolicy{Automated: &argoprojv1alpha1.SyncPolicyAutomated{}},
+				},
+			}
+		})
+
+		decided := func() audit.Record {
+			record := audit.Record{}
+			Expect(json.Unmarshal(buffer.Bytes(), &record)).To(Succeed())
+			return record
+		}
+
+		It("should record an allowed decision for a status update", func() {
+			newApplication := oldApplication.DeepCopy()
+			newApplication.Status.Health.Status = "Healthy"
+
+			_, err := validator.ValidateUpdate(ctx, oldApplication, newApplication)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(decided().Decision).To(Equal(metrics.OutcomeAllowed))
+			Expect(decided().Reason).To(Equal(metrics.ReasonNotSpecUpdate))
+		})
+
+		It("should record an allowed decision for an update disabling the automated sync", func() {
+			disabled := false
+			newApplication := oldApplication.DeepCopy()
+			newApplication.Spec.SyncPolicy.Automated.Enabled = &disabled
+
+			_, err := validator.ValidateUpdate(ctx, oldApplication, newApplication)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(decided().Decision).To(Equal(metrics.OutcomeAllowed))
+			Expect(decided().Reason).To(Equal(metrics.ReasonAutoSyncDisabled))
+		})
+	})
+})
@@ -0,0 +1,30 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+const (
+	// ReasonBypassedByLabel is the reason of the Event recorded when a bypass label skips validation.
+	ReasonBypassedByLabel = "BypassedByLabel"
+	// ReasonBypassedByGrant is the reason of the Event recorded when a BypassGrant skips validation.
+	ReasonBypassedByGrant = "BypassedByGrant"
+	// ReasonManagementApplication is the reason of the Event recorded when validation is skipped for a management
+	// Application.
+	ReasonManagementApplication = "ManagementApplication"
+	// ReasonAdmittedWithViolation is the reason of the Event recorded when an object is admitted despite a
+	// violation because of the warn enforcement mode.
+	ReasonAdmittedWithViolation = "AdmittedWithViolation"
+)
@@ -116,6 +116,47 @@ var testCases = []struct {
 		argoInstanceUsersConfigMapData: testutils.ArgoInstanceUsersConfigMapData,
 		expectToSucceed:                true,
 	},
+	{
+		name: "should allow valid Application when a group admin has permissions",
+		spec: argoprojv1alpha1.ApplicationSpec{
+			Destination: argoprojv1alpha1.ApplicationDestination{
+				Namespace: testutils.TestDestinationNamespace,
+				Server:    testutils.TestDestinationServerName,
+			},
+		},
+		serverTokenKey:                 testutils.TestDestinationServerUrl,
+		argoInstanceNameConfigMapKey:   common.ArgoInstanceNameConfigMapKey,
+		argoInstanceUsersConfigMapKey:  common.ArgoInstanceUsersConfigMapKey,
+		argoInstanceUsersConfigMapData: testutils.GroupArgoInstanceUsersConfigMapData,
+		expectToSucceed:                true,
+	},
+	{
+		name: "should allow valid Application when a ServiceAccount admin has permissions",
+		spec: argoprojv1alpha1.ApplicationSpec{
+			Destination: argoprojv1alpha1.ApplicationDestination{
+				Namespace: testutils.TestDestinationNamespace,
+				Server:    testutils.TestDestinationServerName,
+			},
+		},
+		serverTokenKey:                 testutils.TestDestinationServerUrl,
+		argoInstanceNameConfigMapKey:   common.ArgoInstanceNameConfigMapKey,
+		argoInstanceUsersConfigMapKey:  common.ArgoInstanceUsersConfigMapKey,
+		argoInstanceUsersConfigMapData: testutils.ServiceAccountInstanceUsersData,
+		expectToSucceed:                true,
+	},
+	{
+		name: "should reject Application if the instance users contain a malformed ServiceAccount",
+		spec: argoprojv1alpha1.ApplicationSpec{
+			Destination: argoprojv1alpha1.ApplicationDestination{
+				Namespace: testutils.TestDestinationNamespace,
+				Server:    testutils.TestDestinationServerName,
+			},
+		},
+		serverTokenKey:                 testutils.TestDestinationServerUrl,
+		argoInstanceNameConfigMapKey:   common.ArgoInstanceNameConfigMapKey,
+		argoInstanceUsersConfigMapKey:  common.ArgoInstanceUsersConfigMapKey,
+		argoInstanceUsersConfigMapData: "admin1,serviceaccount:" + testutils.AdminServiceAccountName,
+	},
 	{
 		name: "should allow valid Application with general bypass label",
 		spec: argoprojv1alpha1.ApplicationSpec{
@@ -30,6 +30,7 @@ import (
 	. "github.com/onsi/gomega"
 
 	argoprojv1alpha1 "github.com/argoproj/argo-cd/v3/pkg/apis/application/v1alpha1"
+	rbacv1alpha1 "github.com/dana-team/application-rbac-validator/api/v1alpha1"
 	"k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/rest"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -68,6 +69,9 @@ var _ = BeforeSuite(func() {
 	err = argoprojv1alpha1.AddToScheme(scheme.Scheme)
 	Expect(err).NotTo(HaveOccurred())
 
+	err = rbacv1alpha1.AddToScheme(scheme.Scheme)
+	Expect(err).NotTo(HaveOccurred())
+
 	// +kubebuilder:scaffold:scheme
 
 	By("bootstrapping test environment")
@@ -108,7 +112,16 @@ var _ = BeforeSuite(func() {
 	})
 	Expect(err).NotTo(HaveOccurred())
 
-	err = SetupApplicationWebhookWithManager(mgr, "example.com")
+	err = SetupApplicationWebhookWithManager(mgr, ValidatorConfig{ServerUrlDomain: "example.com"})
+	Expect(err).NotTo(HaveOccurred())
+
+	err = SetupApplicationSetWebhookWithManager(mgr, ValidatorConfig{ServerUrlDomain: "example.com"})
+	Expect(err).NotTo(HaveOccurred())
+
+	err = SetupAppProjectWebhookWithManager(mgr, ValidatorConfig{ServerUrlDomain: "example.com"})
+	Expect(err).NotTo(HaveOccurred())
+
+	err = SetupBypassGrantWebhookWithManager(mgr, BypassGrantConfig{MaxTTL: DefaultBypassGrantMaxTTL})
 	Expect(err).NotTo(HaveOccurred())
 
 	// +kubebuilder:scaffold:webhook
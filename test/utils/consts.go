@@ -15,6 +15,11 @@ const (
 	ErrorTokenServerUrl                   = "error-token-server"
 	ArgoInstanceUsersConfigMapData        = "admin1,admin2,admin3"
 	InvalidArgoInstanceUsersConfigMapData = "admin2,admin3,admin4"
+	AdminGroupName                        = "platform-admins"
+	AdminServiceAccountNamespace          = "ci"
+	AdminServiceAccountName               = "deployer"
+	GroupArgoInstanceUsersConfigMapData   = "admin2,group:" + AdminGroupName
+	ServiceAccountInstanceUsersData       = "group:developers,serviceaccount:" + AdminServiceAccountNamespace + "/" + AdminServiceAccountName
 	ArgoInstanceNameConfigMapData         = "argo-instance-name"
 	InvalidArgoInstanceUsersConfigMapKey  = "not-users"
 	InvalidArgoInstanceNameConfigMapKey   = "not-project-name"
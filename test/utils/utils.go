@@ -2,9 +2,11 @@ package utils
 
 import (
 	"math/rand"
+	"slices"
 	"strings"
 
 	argoprojv1alpha1 "github.com/argoproj/argo-cd/v3/pkg/apis/application/v1alpha1"
+	rbacv1alpha1 "github.com/dana-team/application-rbac-validator/api/v1alpha1"
 	"github.com/dana-team/application-rbac-validator/internal/common"
 	authv1 "k8s.io/api/authorization/v1"
 	corev1 "k8s.io/api/core/v1"
@@ -23,7 +25,9 @@ func NewMockedDestinationClusterClient() *fake.Clientset {
 	client.Fake.PrependReactor("create", "subjectaccessreviews",
 		func(action testing.Action) (bool, runtime.Object, error) {
 			sar := action.(testing.CreateAction).GetObject().(*authv1.SubjectAccessReview)
-			if sar.Spec.User == strings.Split(ArgoInstanceUsersConfigMapData, ",")[0] {
+			if sar.Spec.User == strings.Split(ArgoInstanceUsersConfigMapData, ",")[0] ||
+				sar.Spec.User == "system:serviceaccount:"+AdminServiceAccountNamespace+":"+AdminServiceAccountName ||
+				slices.Contains(sar.Spec.Groups, AdminGroupName) {
 				return true, &authv1.SubjectAccessReview{
 					Status: authv1.SubjectAccessReviewStatus{Allowed: true},
 				}, nil
@@ -91,10 +95,12 @@ func NewFakeClient(initObjs ...client.Object) client.WithWatch {
 	scheme := runtime.NewScheme()
 	_ = corev1.AddToScheme(scheme)
 	_ = argoprojv1alpha1.AddToScheme(scheme)
+	_ = rbacv1alpha1.AddToScheme(scheme)
 
 	return crtfake.NewClientBuilder().
 		WithScheme(scheme).
 		WithObjects(initObjs...).
+		WithStatusSubresource(&rbacv1alpha1.ApplicationRBACPolicy{}, &rbacv1alpha1.BypassGrant{}).
 		Build()
 
 }